@@ -0,0 +1,44 @@
+package form3
+
+// FieldAliases maps the JSON keys of deprecated organisation account fields to the keys
+// of the fields replacing them, e.g. "bank_id" to "sort_code" should the API rename it,
+// so that accounts are decoded whichever key the API sends. Aliases apply to the keys
+// of every object of a response, such as those of the account and of its attributes.
+// See WithFieldAliases.
+type FieldAliases map[string]string
+
+// RegisterFieldAlias maps the deprecated key old to the key new. When a response holds
+// both keys, the value of new is kept.
+func (a FieldAliases) RegisterFieldAlias(old, new string) {
+	a[old] = new
+}
+
+// rename renames the deprecated keys of the objects found in the decoded value data.
+func (a FieldAliases) rename(data interface{}) interface{} {
+	switch data := data.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(data))
+		for key, value := range data {
+			renamed[key] = a.rename(value)
+		}
+		for old, new := range a {
+			value, ok := renamed[old]
+			if !ok {
+				continue
+			}
+			delete(renamed, old)
+			if _, ok := renamed[new]; !ok {
+				renamed[new] = value
+			}
+		}
+		return renamed
+	case []interface{}:
+		renamed := make([]interface{}, len(data))
+		for i, value := range data {
+			renamed[i] = a.rename(value)
+		}
+		return renamed
+	default:
+		return data
+	}
+}
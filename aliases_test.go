@@ -0,0 +1,50 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFieldAliases(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/organisation/accounts" {
+			_, _ = w.Write([]byte(`{"data":[
+				{"type":"accounts","attributes":{"sort_code":"400300"}},
+				{"type":"accounts","attributes":{"bank_id":"400301","sort_code":"400302"}}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"type":"accounts","attributes":{"sort_code":"400300","bank_id_code":"GBDSC"}}}`))
+	}))
+	defer ts.Close()
+
+	aliases := FieldAliases{}
+	aliases.RegisterFieldAlias("sort_code", "bank_id")
+	client := NewClient(ts.URL, WithFieldAliases(aliases))
+
+	// aliases changed after the client is created do not affect it
+	aliases.RegisterFieldAlias("bank_id_code", "country")
+
+	account, err := client.Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, "400300", account.Attributes.BankID)
+	assert.Equal(t, BankIDCode("GBDSC"), account.Attributes.BankIDCode)
+	assert.Equal(t, Country(""), account.Attributes.Country)
+
+	accounts, err := client.List(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, accounts, 2) {
+		assert.Equal(t, "400300", accounts[0].Attributes.BankID)
+		assert.Equal(t, "400301", accounts[1].Attributes.BankID, "the new key wins over the deprecated one")
+	}
+
+	// a client without aliases ignores the deprecated key
+	account, err = NewClient(ts.URL).Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, "", account.Attributes.BankID)
+}
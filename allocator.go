@@ -0,0 +1,91 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ErrAllocatorExhausted is returned by AccountNumberAllocator.Next once every account
+// number of its range was allocated.
+var ErrAllocatorExhausted = errors.New("account number range exhausted")
+
+// AccountNumberAllocator allocates account numbers from a range reserved by the bank,
+// e.g. to set OrganisationAccountAttributes.AccountNumber before calling Create. It is
+// safe for concurrent use. Allocations are only kept in memory: a new allocator for the
+// same range starts again from its beginning.
+type AccountNumberAllocator struct {
+	mu    sync.Mutex
+	next  uint64
+	to    uint64
+	width int
+	done  bool
+}
+
+// NewAllocator returns an allocator of the account numbers from from to to, both
+// included. Both must be numeric, and from must be less than to. Account numbers are
+// zero-padded to the length of from, e.g. "00000100" is followed by "00000101".
+func NewAllocator(from, to string) (*AccountNumberAllocator, error) {
+	first, err := parseAccountNumber(from)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := parseAccountNumber(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if first >= last {
+		return nil, fmt.Errorf("invalid account number range: %s is not less than %s", from, to)
+	}
+
+	return &AccountNumberAllocator{next: first, to: last, width: len(from)}, nil
+}
+
+// parseAccountNumber parses an account number made of digits only.
+func parseAccountNumber(s string) (uint64, error) {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("invalid account number %q: not numeric", s)
+		}
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid account number %q: %w", s, err)
+	}
+	return n, nil
+}
+
+// Next returns the next account number of the range, or ErrAllocatorExhausted if
+// there is none left.
+func (a *AccountNumberAllocator) Next() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.done {
+		return "", ErrAllocatorExhausted
+	}
+
+	n := a.next
+	if n == a.to {
+		a.done = true
+	} else {
+		a.next++
+	}
+
+	return fmt.Sprintf("%0*d", a.width, n), nil
+}
+
+// Remaining returns how many account numbers are left to allocate.
+func (a *AccountNumberAllocator) Remaining() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.done {
+		return 0
+	}
+	return int(a.to - a.next + 1)
+}
@@ -0,0 +1,86 @@
+package form3
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllocator(t *testing.T) {
+	testCases := []struct {
+		name  string
+		from  string
+		to    string
+		valid bool
+	}{
+		{name: "OK - valid range", from: "00000100", to: "00000199", valid: true},
+		{name: "NOK - empty bound", from: "", to: "100"},
+		{name: "NOK - non-numeric bound", from: "100", to: "1A0"},
+		{name: "NOK - signed bound", from: "+100", to: "200"},
+		{name: "NOK - equal bounds", from: "100", to: "100"},
+		{name: "NOK - reversed bounds", from: "200", to: "100"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := NewAllocator(tc.from, tc.to)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.NotNil(t, a)
+			} else {
+				assert.Error(t, err)
+				assert.Nil(t, a)
+			}
+		})
+	}
+}
+
+func TestAccountNumberAllocatorNext(t *testing.T) {
+	a, err := NewAllocator("00000098", "00000100")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, a.Remaining())
+
+	for _, expected := range []string{"00000098", "00000099", "00000100"} {
+		n, err := a.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, n)
+	}
+
+	assert.Equal(t, 0, a.Remaining())
+
+	_, err = a.Next()
+	assert.True(t, errors.Is(err, ErrAllocatorExhausted))
+}
+
+func TestAccountNumberAllocatorConcurrentNext(t *testing.T) {
+	a, err := NewAllocator("1000", "1999")
+	assert.NoError(t, err)
+
+	var (
+		mu        sync.Mutex
+		allocated = map[string]bool{}
+		wg        sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				n, err := a.Next()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				assert.False(t, allocated[n], "allocated %s twice", n)
+				allocated[n] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, allocated, 1000)
+	assert.Equal(t, 0, a.Remaining())
+}
@@ -0,0 +1,60 @@
+package form3
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operations reported in audit events.
+const (
+	AuditOperationCreate = "create"
+	AuditOperationFetch  = "fetch"
+	AuditOperationUpdate = "update"
+	AuditOperationDelete = "delete"
+)
+
+// AuditEvent records an operation performed on an organisation account.
+type AuditEvent struct {
+	// Timestamp is when the operation completed.
+	Timestamp time.Time
+	// Operation is one of the AuditOperation constants.
+	Operation string
+	AccountID uuid.UUID
+	// ActorID identifies who performed the operation, as returned by the actor
+	// extractor set with WithActorExtractor, if any.
+	ActorID string
+	Success bool
+	// ErrorMessage is the error the operation failed with, if it did.
+	ErrorMessage string
+}
+
+// AuditLogger is the interface used by the client to keep an audit trail of the
+// operations performed on organisation accounts. See WithAuditLogger.
+type AuditLogger interface {
+	Log(event AuditEvent)
+}
+
+// audit logs the outcome of an operation on the organisation account with the given ID
+// through the audit logger of the client, if any.
+func (c *Client) audit(ctx context.Context, operation string, accountID uuid.UUID, err error) {
+	if c.auditLogger == nil {
+		return
+	}
+
+	event := AuditEvent{
+		Timestamp: c.clockFunc(),
+		Operation: operation,
+		AccountID: accountID,
+		Success:   err == nil,
+	}
+	if c.actorExtractor != nil {
+		event.ActorID = c.actorExtractor(ctx)
+	}
+	if err != nil {
+		event.ErrorMessage = err.Error()
+	}
+
+	c.auditLogger.Log(event)
+}
@@ -0,0 +1,69 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// auditRecorder is an AuditLogger keeping the events it logs.
+type auditRecorder []AuditEvent
+
+func (r *auditRecorder) Log(event AuditEvent) {
+	*r = append(*r, event)
+}
+
+type actorKey struct{}
+
+func TestWithAuditLogger(t *testing.T) {
+	id := uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_message":"invalid version"}`))
+		case http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			_, _ = w.Write([]byte(`{"data":{"id":"` + id.String() + `"}}`))
+		}
+	}))
+	defer ts.Close()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var recorder auditRecorder
+	client := NewClient(
+		ts.URL,
+		WithAuditLogger(&recorder),
+		WithActorExtractor(func(ctx context.Context) string {
+			actor, _ := ctx.Value(actorKey{}).(string)
+			return actor
+		}),
+	)
+	client.clockFunc = func() time.Time { return now }
+
+	ctx := context.WithValue(context.Background(), actorKey{}, "user-1")
+
+	_, err := client.Create(ctx, OrganisationAccount{ID: id})
+	assert.NoError(t, err)
+	_, err = client.Update(ctx, OrganisationAccount{ID: id})
+	assert.NoError(t, err)
+	_, err = client.Fetch(ctx, id)
+	assert.Error(t, err)
+	err = client.Delete(context.Background(), id, 0)
+	assert.Error(t, err)
+
+	assert.Equal(t, auditRecorder{
+		{Timestamp: now, Operation: AuditOperationCreate, AccountID: id, ActorID: "user-1", Success: true},
+		{Timestamp: now, Operation: AuditOperationUpdate, AccountID: id, ActorID: "user-1", Success: true},
+		{Timestamp: now, Operation: AuditOperationFetch, AccountID: id, ActorID: "user-1", ErrorMessage: "Not Found"},
+		{Timestamp: now, Operation: AuditOperationDelete, AccountID: id, ErrorMessage: "invalid version"},
+	}, recorder)
+}
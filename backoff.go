@@ -0,0 +1,84 @@
+package form3
+
+import (
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+// ConstantBackoff returns a back-off strategy, to be used with WithBackoffStrategy,
+// that always waits the same interval between retries. It never gives up on its
+// own, so requests using it are only bounded by their context.
+func ConstantBackoff(interval time.Duration) func() backoff.BackOff {
+	return func() backoff.BackOff {
+		return backoff.NewConstantBackOff(interval)
+	}
+}
+
+// LinearBackoff returns a back-off strategy, to be used with WithBackoffStrategy,
+// that waits initial before the first retry and increment more before every
+// following one. It gives up after max retries.
+func LinearBackoff(initial, increment time.Duration, max int) func() backoff.BackOff {
+	return func() backoff.BackOff {
+		return &linearBackOff{
+			initial:   initial,
+			increment: increment,
+			max:       max,
+		}
+	}
+}
+
+// linearBackOff is the backoff.BackOff implementation behind LinearBackoff.
+type linearBackOff struct {
+	initial   time.Duration
+	increment time.Duration
+	max       int
+	attempt   int
+}
+
+func (b *linearBackOff) NextBackOff() time.Duration {
+	if b.attempt >= b.max {
+		return backoff.Stop
+	}
+
+	next := b.initial + time.Duration(b.attempt)*b.increment
+	b.attempt++
+
+	return next
+}
+
+func (b *linearBackOff) Reset() {
+	b.attempt = 0
+}
+
+// exponentialBackOff is the back-off strategy used when WithBackoffStrategy is not
+// passed to NewClient. Its intervals can be tuned with the WithBackoff* client options.
+func (c *Client) exponentialBackOff() backoff.BackOff {
+	expBackOff := backoff.NewExponentialBackOff()
+	expBackOff.MaxElapsedTime = backoffMaxElapsedTime
+	expBackOff.Clock = clock(c.clockFunc)
+	expBackOff.RandomizationFactor = c.backoffJitter
+
+	if c.backoffInitialInterval > 0 {
+		expBackOff.InitialInterval = c.backoffInitialInterval
+	}
+	if c.backoffMultiplier > 0 {
+		expBackOff.Multiplier = c.backoffMultiplier
+	}
+	if c.backoffMaxInterval > 0 {
+		expBackOff.MaxInterval = c.backoffMaxInterval
+	}
+
+	// restart the elapsed time using the client's clock
+	expBackOff.Reset()
+
+	return expBackOff
+}
+
+// clock lets a function returning the current time be used as a backoff.Clock.
+type clock func() time.Time
+
+// Now returns the current time.
+func (f clock) Now() time.Time {
+	return f()
+}
@@ -0,0 +1,96 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinearBackoff(t *testing.T) {
+	b := LinearBackoff(10*time.Millisecond, 5*time.Millisecond, 3)()
+
+	assert.Equal(t, 10*time.Millisecond, b.NextBackOff())
+	assert.Equal(t, 15*time.Millisecond, b.NextBackOff())
+	assert.Equal(t, 20*time.Millisecond, b.NextBackOff())
+	assert.Equal(t, backoff.Stop, b.NextBackOff())
+
+	b.Reset()
+
+	assert.Equal(t, 10*time.Millisecond, b.NextBackOff())
+}
+
+func TestWithBackoffStrategy(t *testing.T) {
+	var attempts int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var strategyCalls int
+	client := NewClient(ts.URL, WithBackoffStrategy(func() backoff.BackOff {
+		strategyCalls++
+		return LinearBackoff(time.Millisecond, time.Millisecond, 2)()
+	}))
+
+	_, err := client.List(context.Background())
+	assert.Error(t, err)
+
+	_, err = client.List(context.Background())
+	assert.Error(t, err)
+
+	assert.Equal(t, 2, strategyCalls)
+	assert.Equal(t, 6, attempts)
+}
+
+func TestExponentialBackoffOptions(t *testing.T) {
+	client := NewClient(
+		"http://localhost",
+		WithBackoffInitialInterval(100*time.Millisecond),
+		WithBackoffMultiplier(3),
+		WithBackoffMaxInterval(time.Second),
+	)
+
+	b, ok := client.newBackOff().(*backoff.ExponentialBackOff)
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, b.InitialInterval)
+	assert.Equal(t, float64(3), b.Multiplier)
+	assert.Equal(t, time.Second, b.MaxInterval)
+	assert.Equal(t, backoffMaxElapsedTime, b.MaxElapsedTime)
+}
+
+func TestWithBackoffJitter(t *testing.T) {
+	// retryTimes returns when a client would send its retries if it started at the same
+	// time as the others, as read from a controlled clock that only moves by the waits
+	retryTimes := func(client *Client) []time.Duration {
+		start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		now := start
+		client.clockFunc = func() time.Time { return now }
+
+		b := client.newBackOff()
+
+		var times []time.Duration
+		for i := 0; i < 5; i++ {
+			now = now.Add(b.NextBackOff())
+			times = append(times, now.Sub(start).Truncate(time.Millisecond))
+		}
+		return times
+	}
+
+	withoutJitter := retryTimes(NewClient("http://localhost", WithBackoffJitter(0)))
+	assert.Equal(t, withoutJitter, retryTimes(NewClient("http://localhost", WithBackoffJitter(0))))
+
+	first := retryTimes(NewClient("http://localhost", WithBackoffJitter(0.5)))
+	second := retryTimes(NewClient("http://localhost", WithBackoffJitter(0.5)))
+	// any single retry could collide by chance, but not all of them
+	assert.NotEqual(t, first, second)
+
+	b := NewClient("http://localhost", WithBackoffJitter(2)).newBackOff().(*backoff.ExponentialBackOff)
+	assert.Equal(t, float64(1), b.RandomizationFactor)
+}
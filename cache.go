@@ -0,0 +1,182 @@
+package form3
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheStats holds the counters of a CachedClient.
+type CacheStats struct {
+	// Hits is the number of Fetch calls served from the cache.
+	Hits int64
+	// Misses is the number of Fetch calls forwarded to the inner account service.
+	Misses int64
+	// Evictions is the number of entries removed from the cache, either because
+	// they expired or because the account was created or deleted.
+	Evictions int64
+}
+
+// CachedClient is an AccountService that serves Fetch calls from memory for as long
+// as the fetched accounts are fresh.
+type CachedClient struct {
+	inner   AccountService
+	ttl     time.Duration
+	entries sync.Map
+
+	// mu guards the generations, so that an account read before it was invalidated is
+	// never cached after that: generation is bumped by every invalidation, and
+	// invalidatedAt holds the generation at which each account was last invalidated
+	mu            sync.Mutex
+	generation    uint64
+	invalidatedAt map[uuid.UUID]uint64
+
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+// cacheEntry is an organisation account held in the cache until it expires.
+type cacheEntry struct {
+	organisationAccount OrganisationAccount
+	expiresAt           time.Time
+}
+
+var _ AccountService = (*CachedClient)(nil)
+
+// NewCachedClient wraps the given account service so that accounts it fetches or
+// lists are kept in memory for ttl, during which fetching them again does not reach
+// the inner service. Creating or deleting an account drops it from the cache.
+func NewCachedClient(inner AccountService, ttl time.Duration) *CachedClient {
+	return &CachedClient{
+		inner:         inner,
+		ttl:           ttl,
+		invalidatedAt: map[uuid.UUID]uint64{},
+	}
+}
+
+// Create creates the organisation account, then drops any cached copy of it. The
+// copy is dropped after the inner service returns, even with an error, and a Fetch
+// or List that started before is not allowed to cache the account it read, so that
+// the account as it was before Create is not served afterwards.
+func (c *CachedClient) Create(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (OrganisationAccount, error) {
+	created, err := c.inner.Create(ctx, organisationAccount, roo...)
+	c.invalidate(organisationAccount.ID)
+
+	return created, err
+}
+
+// Fetch returns the cached organisation account if it has not expired yet, and
+// fetches it from the inner account service otherwise. Calls given request options
+// bypass the cache, since the options may change what the service returns.
+func (c *CachedClient) Fetch(ctx context.Context, accountID uuid.UUID, roo ...RequestOption) (OrganisationAccount, error) {
+	if len(roo) > 0 {
+		return c.inner.Fetch(ctx, accountID, roo...)
+	}
+
+	if value, ok := c.entries.Load(accountID); ok {
+		entry := value.(cacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			atomic.AddInt64(&c.hits, 1)
+			return entry.organisationAccount, nil
+		}
+
+		c.invalidate(accountID)
+	}
+
+	atomic.AddInt64(&c.misses, 1)
+
+	since := c.currentGeneration()
+	organisationAccount, err := c.inner.Fetch(ctx, accountID)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	c.store(organisationAccount, since)
+
+	return organisationAccount, nil
+}
+
+// List lists the organisation accounts using the inner account service and caches
+// each one of them, unless the list options select fields or include related
+// resources, in which case the listed accounts differ from the fetched ones.
+func (c *CachedClient) List(ctx context.Context, loo ...ListOption) ([]OrganisationAccount, error) {
+	since := c.currentGeneration()
+	organisationAccounts, err := c.inner.List(ctx, loo...)
+	if err != nil {
+		return nil, err
+	}
+
+	var options listOptions
+	for _, lo := range loo {
+		lo(&options)
+	}
+
+	if options.partial() {
+		return organisationAccounts, nil
+	}
+
+	for _, organisationAccount := range organisationAccounts {
+		c.store(organisationAccount, since)
+	}
+
+	return organisationAccounts, nil
+}
+
+// Delete deletes the organisation account, then drops any cached copy of it, like
+// Create does.
+func (c *CachedClient) Delete(ctx context.Context, accountID uuid.UUID, version int) error {
+	err := c.inner.Delete(ctx, accountID, version)
+	c.invalidate(accountID)
+
+	return err
+}
+
+// CacheStats returns a snapshot of the counters of the cache.
+func (c *CachedClient) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadInt64(&c.hits),
+		Misses:    atomic.LoadInt64(&c.misses),
+		Evictions: atomic.LoadInt64(&c.evictions),
+	}
+}
+
+// currentGeneration returns the generation to pass to store for the accounts read from
+// now on.
+func (c *CachedClient) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// store caches the organisation account read at the given generation, unless it was
+// invalidated since.
+func (c *CachedClient) store(organisationAccount OrganisationAccount, since uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.invalidatedAt[organisationAccount.ID] > since {
+		return
+	}
+
+	c.entries.Store(organisationAccount.ID, cacheEntry{
+		organisationAccount: organisationAccount,
+		expiresAt:           time.Now().Add(c.ttl),
+	})
+}
+
+func (c *CachedClient) invalidate(accountID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.invalidatedAt[accountID] = c.generation
+
+	if _, ok := c.entries.LoadAndDelete(accountID); ok {
+		atomic.AddInt64(&c.evictions, 1)
+	}
+}
@@ -0,0 +1,168 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	fake := newFakeAccountService()
+	client := NewCachedClient(fake, time.Hour)
+
+	_, err := client.Create(ctx, OrganisationAccount{ID: id, Version: 0})
+	assert.NoError(t, err)
+
+	// first fetch misses, second one hits
+	_, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	_, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, client.CacheStats())
+
+	// changes made behind the cache's back are not seen until the entry is dropped
+	fake.accounts[id] = OrganisationAccount{ID: id, Version: 1}
+	organisationAccount, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, organisationAccount.Version)
+
+	assert.NoError(t, client.Delete(ctx, id, 1))
+	_, err = client.Fetch(ctx, id)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Evictions: 1}, client.CacheStats())
+
+	// listed accounts are cached
+	_, err = client.Create(ctx, OrganisationAccount{ID: id})
+	assert.NoError(t, err)
+	_, err = client.List(ctx)
+	assert.NoError(t, err)
+	_, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 2, Evictions: 1}, client.CacheStats())
+}
+
+func TestCachedClientExpiry(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	fake := newFakeAccountService()
+	fake.accounts[id] = OrganisationAccount{ID: id}
+	client := NewCachedClient(fake, time.Millisecond)
+
+	_, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{Misses: 2, Evictions: 1}, client.CacheStats())
+}
+
+// racingAccountService calls beforeWrite at the start of Create and Delete, e.g. to
+// fetch the account while it is being written.
+type racingAccountService struct {
+	*fakeAccountService
+	beforeWrite func()
+}
+
+func (r racingAccountService) Create(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (OrganisationAccount, error) {
+	r.beforeWrite()
+	return r.fakeAccountService.Create(ctx, organisationAccount, roo...)
+}
+
+func (r racingAccountService) Delete(ctx context.Context, accountID uuid.UUID, version int) error {
+	r.beforeWrite()
+	return r.fakeAccountService.Delete(ctx, accountID, version)
+}
+
+func TestCachedClientWriteRacingWithFetch(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	fake := newFakeAccountService()
+	fake.accounts[id] = OrganisationAccount{ID: id, Version: 0}
+
+	var client *CachedClient
+	client = NewCachedClient(racingAccountService{
+		fakeAccountService: fake,
+		beforeWrite: func() {
+			_, _ = client.Fetch(ctx, id)
+		},
+	}, time.Hour)
+
+	_, err := client.Create(ctx, OrganisationAccount{ID: id, Version: 1})
+	assert.NoError(t, err)
+	organisationAccount, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, organisationAccount.Version)
+
+	assert.NoError(t, client.Delete(ctx, id, 1))
+	_, err = client.Fetch(ctx, id)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestCachedClientPartialList(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	fake := newFakeAccountService()
+	fake.accounts[id] = OrganisationAccount{ID: id}
+	client := NewCachedClient(fake, time.Hour)
+
+	for _, lo := range []ListOption{SparseFieldset("id"), IncludeRelated("organisation")} {
+		_, err := client.List(ctx, lo)
+		assert.NoError(t, err)
+	}
+
+	_, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, CacheStats{Misses: 1}, client.CacheStats())
+}
+
+// slowFetchAccountService calls duringFetch after reading the account and before
+// returning it, e.g. to write the account while it is being fetched.
+type slowFetchAccountService struct {
+	*fakeAccountService
+	duringFetch func()
+}
+
+func (s slowFetchAccountService) Fetch(ctx context.Context, accountID uuid.UUID, roo ...RequestOption) (OrganisationAccount, error) {
+	organisationAccount, err := s.fakeAccountService.Fetch(ctx, accountID, roo...)
+	if s.duringFetch != nil {
+		s.duringFetch()
+	}
+	return organisationAccount, err
+}
+
+func TestCachedClientFetchRacingWithWrite(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	fake := newFakeAccountService()
+	fake.accounts[id] = OrganisationAccount{ID: id, Version: 0}
+
+	service := &slowFetchAccountService{fakeAccountService: fake}
+	client := NewCachedClient(service, time.Hour)
+
+	// the fetch reads version 0, then the account is updated before the fetch returns
+	service.duringFetch = func() {
+		service.duringFetch = nil
+		_, err := client.Create(ctx, OrganisationAccount{ID: id, Version: 1})
+		assert.NoError(t, err)
+	}
+	organisationAccount, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, organisationAccount.Version)
+
+	organisationAccount, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, organisationAccount.Version)
+}
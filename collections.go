@@ -0,0 +1,42 @@
+package form3
+
+import "github.com/google/uuid"
+
+// AccountsByID returns the given organisation accounts keyed by their ID. If several
+// accounts share an ID, the last one wins.
+func AccountsByID(accounts []OrganisationAccount) map[uuid.UUID]OrganisationAccount {
+	byID := make(map[uuid.UUID]OrganisationAccount, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	return byID
+}
+
+// AccountsByAccountNumber returns the given organisation accounts keyed by their account
+// number, leaving out the ones without any. If several accounts share an account number,
+// e.g. because they are held by different banks, the last one wins.
+func AccountsByAccountNumber(accounts []OrganisationAccount) map[string]OrganisationAccount {
+	byAccountNumber := make(map[string]OrganisationAccount, len(accounts))
+	for _, account := range accounts {
+		if account.Attributes.AccountNumber == nil {
+			continue
+		}
+		byAccountNumber[*account.Attributes.AccountNumber] = account
+	}
+
+	return byAccountNumber
+}
+
+// FilterAccounts returns the organisation accounts for which pred returns true, in the
+// order they are given in.
+func FilterAccounts(accounts []OrganisationAccount, pred func(OrganisationAccount) bool) []OrganisationAccount {
+	var filtered []OrganisationAccount
+	for _, account := range accounts {
+		if pred(account) {
+			filtered = append(filtered, account)
+		}
+	}
+
+	return filtered
+}
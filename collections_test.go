@@ -0,0 +1,32 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountCollections(t *testing.T) {
+	accounts := []OrganisationAccount{
+		{ID: uuid.New(), Attributes: OrganisationAccountAttributes{AccountNumber: String("41426819"), Country: CountryGB}},
+		{ID: uuid.New(), Attributes: OrganisationAccountAttributes{Country: CountryFR}},
+		{ID: uuid.New(), Attributes: OrganisationAccountAttributes{AccountNumber: String("12345678"), Country: CountryGB}},
+	}
+
+	byID := AccountsByID(accounts)
+	assert.Len(t, byID, 3)
+	for _, account := range accounts {
+		assert.Equal(t, account, byID[account.ID])
+	}
+
+	byAccountNumber := AccountsByAccountNumber(accounts)
+	assert.Len(t, byAccountNumber, 2)
+	assert.Equal(t, accounts[0], byAccountNumber["41426819"])
+	assert.Equal(t, accounts[2], byAccountNumber["12345678"])
+
+	british := FilterAccounts(accounts, func(o OrganisationAccount) bool {
+		return o.Attributes.Country == CountryGB
+	})
+	assert.Equal(t, []OrganisationAccount{accounts[0], accounts[2]}, british)
+}
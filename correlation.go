@@ -0,0 +1,38 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+)
+
+// correlationIDHeader is the header carrying the correlation ID of outbound requests.
+const correlationIDHeader = "X-Correlation-ID"
+
+// incomingHeaderKey is the key of the context value holding the headers of the
+// incoming HTTP request being served.
+type incomingHeaderKey struct{}
+
+// ContextWithIncomingHeader returns a copy of ctx holding the headers of the incoming HTTP
+// request being served, for HTTPCorrelationIDExtractor to read them. IncomingHeaderMiddleware
+// does this for every request served by a handler.
+func ContextWithIncomingHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, incomingHeaderKey{}, header)
+}
+
+// IncomingHeaderMiddleware wraps an HTTP handler so that the context of every request
+// it serves holds the request headers, for HTTPCorrelationIDExtractor to read them.
+func IncomingHeaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ContextWithIncomingHeader(r.Context(), r.Header)))
+	})
+}
+
+// HTTPCorrelationIDExtractor returns a correlation ID extractor, to be used with
+// WithCorrelationIDExtractor, that reads the given header of the incoming HTTP request
+// stored in the context by IncomingHeaderMiddleware or ContextWithIncomingHeader.
+func HTTPCorrelationIDExtractor(headerName string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		header, _ := ctx.Value(incomingHeaderKey{}).(http.Header)
+		return header.Get(headerName)
+	}
+}
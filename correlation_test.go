@@ -0,0 +1,44 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCorrelationIDExtractor(t *testing.T) {
+	var seen []string
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("X-Correlation-ID"))
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer api.Close()
+
+	client := NewClient(api.URL, WithCorrelationIDExtractor(HTTPCorrelationIDExtractor("X-Request-ID")))
+
+	// a service calling the API while serving its own requests
+	service := httptest.NewServer(IncomingHeaderMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := client.Fetch(r.Context(), uuid.New())
+		assert.NoError(t, err)
+	})))
+	defer service.Close()
+
+	req, err := http.NewRequest(http.MethodGet, service.URL, nil)
+	assert.NoError(t, err)
+	req.Header.Set("X-Request-ID", "request-42")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	// outside of any incoming request, no header is sent
+	_, err = client.Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"request-42", ""}, seen)
+}
@@ -0,0 +1,73 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Count returns the number of organisation accounts selected by the list options,
+// ignoring any paging option. It requests a single account and reads the total from
+// the meta.total_count field of the response, so that the accounts themselves are not
+// transferred. If the API does not report a total, or if there are more IDs to filter
+// by than fit in a single request, Count falls back to ListAll, which fetches every
+// account and thus costs as many requests as there are pages.
+func (c *Client) Count(ctx context.Context, loo ...ListOption) (int, error) {
+	options := listOptions{}
+	for _, lo := range loo {
+		lo(&options)
+	}
+
+	if options.err != nil {
+		return 0, options.err
+	}
+
+	if c.maxFilterIDs > 0 && len(options.ids) > c.maxFilterIDs {
+		return c.countAll(ctx, loo)
+	}
+
+	options.pageNumber, options.pageNumberSet = 0, false
+	options.pageSize, options.pageSizeSet = 1, true
+
+	url, err := c.listURL(options)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.performRequest(ctx, http.MethodGet, url, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	err = c.checkErrorMessage(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	var body struct {
+		Meta struct {
+			TotalCount *int `json:"total_count"`
+		} `json:"meta"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return 0, err
+	}
+
+	if body.Meta.TotalCount == nil {
+		return c.countAll(ctx, loo)
+	}
+
+	return *body.Meta.TotalCount, nil
+}
+
+// countAll counts the organisation accounts by listing all of them.
+func (c *Client) countAll(ctx context.Context, loo []ListOption) (int, error) {
+	organisationAccounts, err := c.ListAll(ctx, loo...)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(organisationAccounts), nil
+}
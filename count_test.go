@@ -0,0 +1,43 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	t.Run("OK - total reported by the API", func(t *testing.T) {
+		var requests int
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, "1", r.URL.Query().Get("page[size]"))
+			assert.Equal(t, "Personal", r.URL.Query().Get("filter[account_classification]"))
+			_, _ = w.Write([]byte(`{"data":[{}],"meta":{"total_count":42}}`))
+		}))
+		defer ts.Close()
+
+		client := NewClient(ts.URL)
+
+		count, err := client.Count(context.Background(), FilterByAccountClassification(ClassificationPersonal), PageSizeListOption(10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 42, count)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("OK - falls back to listing all accounts", func(t *testing.T) {
+		ts := newPagingServer(newTestAccounts(5))
+		defer ts.Close()
+
+		client := NewClient(ts.URL, WithDefaultPageSize(2))
+
+		count, err := client.Count(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+	})
+}
@@ -0,0 +1,80 @@
+package form3
+
+// Country is the ISO 3166-1 alpha-2 code of the country an account is held in.
+type Country string
+
+// BankIDCode identifies the type of the bank ID of an account, which depends
+// on the country the account is held in.
+type BankIDCode string
+
+// Countries supported by the Form3 API.
+const (
+	CountryAU Country = "AU"
+	CountryBE Country = "BE"
+	CountryCA Country = "CA"
+	CountryCH Country = "CH"
+	CountryDE Country = "DE"
+	CountryES Country = "ES"
+	CountryFR Country = "FR"
+	CountryGB Country = "GB"
+	CountryGR Country = "GR"
+	CountryHK Country = "HK"
+	CountryIT Country = "IT"
+	CountryLU Country = "LU"
+	CountryNL Country = "NL"
+	CountryPL Country = "PL"
+	CountryPT Country = "PT"
+	CountryUS Country = "US"
+)
+
+// Bank ID codes documented by the Form3 API.
+const (
+	BankIDCodeAUBSB BankIDCode = "AUBSB"
+	BankIDCodeBE    BankIDCode = "BE"
+	BankIDCodeCACPA BankIDCode = "CACPA"
+	BankIDCodeCHBCC BankIDCode = "CHBCC"
+	BankIDCodeDEBLZ BankIDCode = "DEBLZ"
+	BankIDCodeESNCC BankIDCode = "ESNCC"
+	BankIDCodeFR    BankIDCode = "FR"
+	BankIDCodeGBDSC BankIDCode = "GBDSC"
+	BankIDCodeGRBIC BankIDCode = "GRBIC"
+	BankIDCodeHKNCC BankIDCode = "HKNCC"
+	BankIDCodeITNCC BankIDCode = "ITNCC"
+	BankIDCodeLULUX BankIDCode = "LULUX"
+	BankIDCodePLKNR BankIDCode = "PLKNR"
+	BankIDCodePTNCC BankIDCode = "PTNCC"
+	BankIDCodeUSABA BankIDCode = "USABA"
+)
+
+// bankIDCodesByCountry maps every supported country to the bank ID codes
+// accepted for it. Countries without a bank ID code (e.g. the Netherlands,
+// where accounts are identified by their BIC) map to no codes.
+var bankIDCodesByCountry = map[Country][]BankIDCode{
+	CountryAU: {BankIDCodeAUBSB},
+	CountryBE: {BankIDCodeBE},
+	CountryCA: {BankIDCodeCACPA},
+	CountryCH: {BankIDCodeCHBCC},
+	CountryDE: {BankIDCodeDEBLZ},
+	CountryES: {BankIDCodeESNCC},
+	CountryFR: {BankIDCodeFR},
+	CountryGB: {BankIDCodeGBDSC},
+	CountryGR: {BankIDCodeGRBIC},
+	CountryHK: {BankIDCodeHKNCC},
+	CountryIT: {BankIDCodeITNCC},
+	CountryLU: {BankIDCodeLULUX},
+	CountryNL: {},
+	CountryPL: {BankIDCodePLKNR},
+	CountryPT: {BankIDCodePTNCC},
+	CountryUS: {BankIDCodeUSABA},
+}
+
+// ValidBankIDCodesForCountry returns the bank ID codes accepted for accounts held
+// in the given country, or nil if the country is not supported by the Form3 API.
+func ValidBankIDCodesForCountry(country Country) []BankIDCode {
+	codes, ok := bankIDCodesByCountry[country]
+	if !ok {
+		return nil
+	}
+
+	return append([]BankIDCode{}, codes...)
+}
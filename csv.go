@@ -0,0 +1,196 @@
+package form3
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// csvMultiValueSeparator separates the values of multi-value fields, such as the
+// names of the account, inside a single CSV cell.
+const csvMultiValueSeparator = ";"
+
+// CSVHeader is the header row matching the cells returned by ToCSVRow. Column names
+// are the JSON field names of the account and its attributes.
+var CSVHeader = []string{
+	"id",
+	"type",
+	"organisation_id",
+	"version",
+	"country",
+	"base_currency",
+	"account_number",
+	"bank_id",
+	"bank_id_code",
+	"bic",
+	"iban",
+	"name",
+	"alternative_names",
+	"account_classification",
+	"joint_account",
+	"account_matching_opt_out",
+	"secondary_identification",
+	"switched",
+}
+
+// ToCSVRow returns the account as a CSV row whose cells follow CSVHeader. Multi-value
+// fields are joined by semicolons, thus their values must not contain any, while optional
+// fields that are not set are left empty.
+func (o OrganisationAccount) ToCSVRow() []string {
+	attrs := o.Attributes
+
+	return []string{
+		o.ID.String(),
+		o.Type,
+		o.OrganisationID.String(),
+		strconv.Itoa(o.Version),
+		string(attrs.Country),
+		attrs.BaseCurrency,
+		stringValue(attrs.AccountNumber),
+		attrs.BankID,
+		string(attrs.BankIDCode),
+		stringValue(attrs.BIC),
+		stringValue(attrs.IBAN),
+		strings.Join(attrs.Name, csvMultiValueSeparator),
+		strings.Join(attrs.AlternativeNames, csvMultiValueSeparator),
+		string(attrs.AccountClassification),
+		formatOptionalBool(attrs.JointAccount),
+		strconv.FormatBool(attrs.AccountMatchingOptOut),
+		stringValue(attrs.SecondaryIdentification),
+		formatOptionalBool(attrs.Switched),
+	}
+}
+
+// OrganisationAccountFromCSVRow parses an account from a CSV row, using headers to
+// know which field each cell holds. Columns can come in any order and can be
+// missing, in which case the matching field is left to its zero value.
+func OrganisationAccountFromCSVRow(headers, values []string) (OrganisationAccount, error) {
+	if len(headers) != len(values) {
+		return OrganisationAccount{}, fmt.Errorf("CSV row has %d cells, but there are %d headers", len(values), len(headers))
+	}
+
+	var o OrganisationAccount
+	attrs := &o.Attributes
+
+	for i, header := range headers {
+		value := values[i]
+
+		var err error
+		switch header {
+		case "id":
+			o.ID, err = parseOptionalUUID(value)
+		case "type":
+			o.Type = value
+		case "organisation_id":
+			o.OrganisationID, err = parseOptionalUUID(value)
+		case "version":
+			if value != "" {
+				o.Version, err = strconv.Atoi(value)
+			}
+		case "country":
+			attrs.Country = Country(value)
+		case "base_currency":
+			attrs.BaseCurrency = value
+		case "account_number":
+			attrs.AccountNumber = optionalString(value)
+		case "bank_id":
+			attrs.BankID = value
+		case "bank_id_code":
+			attrs.BankIDCode = BankIDCode(value)
+		case "bic":
+			attrs.BIC = optionalString(value)
+		case "iban":
+			attrs.IBAN = optionalString(value)
+		case "name":
+			attrs.Name = splitMultiValue(value)
+		case "alternative_names":
+			attrs.AlternativeNames = splitMultiValue(value)
+		case "account_classification":
+			attrs.AccountClassification = AccountClassification(value)
+		case "joint_account":
+			attrs.JointAccount, err = parseOptionalBool(value)
+		case "account_matching_opt_out":
+			if value != "" {
+				attrs.AccountMatchingOptOut, err = strconv.ParseBool(value)
+			}
+		case "secondary_identification":
+			attrs.SecondaryIdentification = optionalString(value)
+		case "switched":
+			attrs.Switched, err = parseOptionalBool(value)
+		default:
+			return OrganisationAccount{}, fmt.Errorf("unknown CSV column %q", header)
+		}
+
+		if err != nil {
+			return OrganisationAccount{}, fmt.Errorf("parsing CSV column %q: %w", header, err)
+		}
+	}
+
+	return o, nil
+}
+
+// OrganisationAccountsToCSV writes the accounts as a CSV file to w, starting with CSVHeader.
+func OrganisationAccountsToCSV(w io.Writer, accounts []OrganisationAccount) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(CSVHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		err = cw.Write(account.ToCSVRow())
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatOptionalBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func parseOptionalBool(s string) (*bool, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func parseOptionalUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
+
+func splitMultiValue(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, csvMultiValueSeparator)
+}
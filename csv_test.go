@@ -0,0 +1,98 @@
+package form3
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	accounts := []OrganisationAccount{
+		{
+			ID:             uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+			Type:           "accounts",
+			OrganisationID: uuid.MustParse("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+			Version:        2,
+			Attributes: OrganisationAccountAttributes{
+				Country:               CountryGB,
+				BaseCurrency:          "GBP",
+				AccountNumber:         String("41426819"),
+				BankID:                "400300",
+				BankIDCode:            BankIDCodeGBDSC,
+				BIC:                   String("NWBKGB22"),
+				Name:                  []string{"Samantha Holder", "Sam, Holder"},
+				AlternativeNames:      []string{"Sam Holder"},
+				AccountClassification: ClassificationPersonal,
+				JointAccount:          Bool(false),
+			},
+		},
+		{
+			ID: uuid.MustParse("bd27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		},
+	}
+
+	var buf bytes.Buffer
+	err := OrganisationAccountsToCSV(&buf, accounts)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, CSVHeader, records[0])
+
+	for i, record := range records[1:] {
+		account, err := OrganisationAccountFromCSVRow(records[0], record)
+		assert.NoError(t, err)
+		assert.Equal(t, accounts[i], account)
+	}
+}
+
+func TestOrganisationAccountFromCSVRow(t *testing.T) {
+	testCases := []struct {
+		name        string
+		headers     []string
+		values      []string
+		expected    OrganisationAccount
+		expectedErr bool
+	}{
+		{
+			name:     "subset of columns in any order",
+			headers:  []string{"name", "country"},
+			values:   []string{"Jane;Doe", "FR"},
+			expected: OrganisationAccount{Attributes: OrganisationAccountAttributes{Country: CountryFR, Name: []string{"Jane", "Doe"}}},
+		},
+		{
+			name:        "unknown column",
+			headers:     []string{"colour"},
+			values:      []string{"blue"},
+			expectedErr: true,
+		},
+		{
+			name:        "malformed version",
+			headers:     []string{"version"},
+			values:      []string{"two"},
+			expectedErr: true,
+		},
+		{
+			name:        "mismatching number of cells",
+			headers:     []string{"id", "type"},
+			values:      []string{"accounts"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			account, err := OrganisationAccountFromCSVRow(tc.headers, tc.values)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, account)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDebugBodySize is the number of bytes of each body kept by Debug.
+const maxDebugBodySize = 64 << 10
+
+// redactedDebugHeaders are the headers holding credentials, whose values Debug does not
+// record.
+var redactedDebugHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// DebugOutput holds the HTTP traffic recorded by Debug.
+type DebugOutput struct {
+	// Requests and Responses are in the order the requests were sent. A request that
+	// failed without a response has none in Responses, thus Requests[i] does not
+	// necessarily match Responses[i]: use the method and URL of the response, or
+	// Exchanges, to pair them.
+	Requests  []DebugRequest
+	Responses []DebugResponse
+
+	// Exchanges pairs each request with its outcome, in the order they were sent.
+	Exchanges []DebugExchange
+}
+
+// DebugExchange is an HTTP request recorded by Debug, along with its outcome.
+type DebugExchange struct {
+	Request DebugRequest
+	// Response is nil if the request failed without a response, in which case Err
+	// holds the reason.
+	Response *DebugResponse
+	Err      error
+	Start    time.Time
+	Duration time.Duration
+}
+
+// DebugRequest is an HTTP request recorded by Debug. Its credential headers, such as
+// Authorization, are redacted.
+type DebugRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	// Body holds at most the first 64 KB of the request body.
+	Body []byte
+}
+
+// DebugResponse is an HTTP response recorded by Debug, along with the method and
+// URL of the request it answers.
+type DebugResponse struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	// Body holds at most the first 64 KB of the response body.
+	Body []byte
+}
+
+// String returns the recorded traffic in a format close to the one used on the wire.
+func (o DebugOutput) String() string {
+	var sb strings.Builder
+
+	for _, exchange := range o.Exchanges {
+		req := exchange.Request
+		fmt.Fprintf(&sb, "> %s %s\n", req.Method, req.URL)
+		writeDebugHeader(&sb, "> ", req.Header)
+		writeDebugBody(&sb, req.Body)
+
+		if resp := exchange.Response; resp != nil {
+			fmt.Fprintf(&sb, "< %d %s (%s)\n", resp.StatusCode, http.StatusText(resp.StatusCode), exchange.Duration)
+			writeDebugHeader(&sb, "< ", resp.Header)
+			writeDebugBody(&sb, resp.Body)
+		} else if exchange.Err != nil {
+			fmt.Fprintf(&sb, "< error: %v (%s)\n", exchange.Err, exchange.Duration)
+		}
+	}
+
+	return sb.String()
+}
+
+func writeDebugHeader(sb *strings.Builder, prefix string, header http.Header) {
+	for key, values := range header {
+		for _, value := range values {
+			fmt.Fprintf(sb, "%s%s: %s\n", prefix, key, value)
+		}
+	}
+}
+
+func writeDebugBody(sb *strings.Builder, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	sb.Write(body)
+	sb.WriteString("\n")
+}
+
+// redactDebugHeader returns a copy of header without the values of its credential
+// headers.
+func redactDebugHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, key := range redactedDebugHeaders {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = []string{"[REDACTED]"}
+		}
+	}
+
+	return redacted
+}
+
+// debugRecorderKey is the key of the context value holding the recorder of Debug.
+type debugRecorderKey struct{}
+
+// Debug calls fn and records the HTTP requests performed with the context
+// given to fn, along with their responses or errors, e.g. to find out why a call to
+// the API fails. Requests made by other goroutines with other contexts are neither
+// affected nor recorded. fn's error, if any, is returned along with the recorded
+// traffic.
+func (c *Client) Debug(ctx context.Context, fn func(ctx context.Context) error) (DebugOutput, error) {
+	rec := &debugRecorder{}
+
+	err := fn(context.WithValue(ctx, debugRecorderKey{}, rec))
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	output := DebugOutput{Exchanges: append([]DebugExchange(nil), rec.exchanges...)}
+	for _, exchange := range output.Exchanges {
+		output.Requests = append(output.Requests, exchange.Request)
+		if exchange.Response != nil {
+			output.Responses = append(output.Responses, *exchange.Response)
+		}
+	}
+
+	return output, err
+}
+
+// debugRecorderFromContext returns the recorder stored in ctx by Debug, if any.
+func debugRecorderFromContext(ctx context.Context) *debugRecorder {
+	rec, _ := ctx.Value(debugRecorderKey{}).(*debugRecorder)
+	return rec
+}
+
+// peekBody returns a copy of the first n bytes of the response body, while leaving the
+// whole of it to be read from the response.
+func peekBody(resp *http.Response, n int64) ([]byte, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, n))
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(body), resp.Body),
+		Closer: resp.Body,
+	}
+
+	return body, nil
+}
+
+// debugRecorder records the requests performed with the context of Debug.
+type debugRecorder struct {
+	mu        sync.Mutex
+	exchanges []DebugExchange
+}
+
+// start records req as it is about to be sent, and returns the index of its exchange.
+func (rec *debugRecorder) start(req *http.Request) int {
+	recordedReq := DebugRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: redactDebugHeader(req.Header),
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			recordedReq.Body, _ = ioutil.ReadAll(io.LimitReader(body, maxDebugBodySize))
+			body.Close()
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.exchanges = append(rec.exchanges, DebugExchange{
+		Request: recordedReq,
+		Start:   time.Now(),
+	})
+
+	return len(rec.exchanges) - 1
+}
+
+// finish records the outcome of the exchange at index i, and returns the response
+// with its body left whole to be read by the caller.
+func (rec *debugRecorder) finish(i int, req *http.Request, resp *http.Response, err error) (*http.Response, error) {
+	var recordedResp *DebugResponse
+	if err == nil {
+		var body []byte
+		body, err = peekBody(resp, maxDebugBodySize)
+		if err != nil {
+			resp.Body.Close()
+			resp = nil
+		} else {
+			recordedResp = &DebugResponse{
+				Method:     req.Method,
+				URL:        req.URL.String(),
+				StatusCode: resp.StatusCode,
+				Header:     redactDebugHeader(resp.Header),
+				Body:       body,
+			}
+		}
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	exchange := &rec.exchanges[i]
+	exchange.Response = recordedResp
+	exchange.Err = err
+	exchange.Duration = time.Since(exchange.Start)
+
+	return resp, err
+}
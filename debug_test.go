@@ -0,0 +1,94 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebug(t *testing.T) {
+	largeName := strings.Repeat("a", 2*maxDebugBodySize)
+	brokenID := uuid.New()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, brokenID.String()):
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error_message":"validation failure"}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":{"attributes":{"name":["` + largeName + `"]}}}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithAPIKey("secret"))
+
+	var fetched OrganisationAccount
+	output, err := client.Debug(context.Background(), func(ctx context.Context) error {
+		_, err := client.Fetch(ctx, brokenID)
+		assert.Error(t, err)
+
+		// requests made without the context of Debug are not recorded
+		_, err = client.Fetch(context.Background(), uuid.Nil)
+		assert.NoError(t, err)
+
+		fetched, err = client.Fetch(ctx, uuid.Nil)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Create(ctx, OrganisationAccount{ID: uuid.Nil})
+		return err
+	})
+
+	assert.Error(t, err)
+
+	// the client still sees the whole response body
+	assert.Equal(t, []string{largeName}, fetched.Attributes.Name)
+
+	if assert.Len(t, output.Exchanges, 3) {
+		failed := output.Exchanges[0]
+		assert.Contains(t, failed.Request.URL, brokenID.String())
+		assert.Nil(t, failed.Response)
+		assert.Error(t, failed.Err)
+
+		fetch := output.Exchanges[1]
+		assert.Equal(t, http.MethodGet, fetch.Request.Method)
+		assert.Contains(t, fetch.Request.URL, uuid.Nil.String())
+		assert.Equal(t, "[REDACTED]", fetch.Request.Header.Get("Authorization"))
+		if assert.NotNil(t, fetch.Response) {
+			assert.Len(t, fetch.Response.Body, maxDebugBodySize)
+		}
+		assert.NoError(t, fetch.Err)
+		assert.False(t, fetch.Start.IsZero())
+
+		create := output.Exchanges[2]
+		assert.Equal(t, http.MethodPost, create.Request.Method)
+		assert.Contains(t, string(create.Request.Body), uuid.Nil.String())
+		if assert.NotNil(t, create.Response) {
+			assert.Equal(t, http.StatusBadRequest, create.Response.StatusCode)
+			assert.Equal(t, `{"error_message":"validation failure"}`, string(create.Response.Body))
+		}
+	}
+
+	// the failed request has no response, thus there is one response less
+	if assert.Len(t, output.Requests, 3) && assert.Len(t, output.Responses, 2) {
+		assert.Contains(t, output.Requests[0].URL, brokenID.String())
+		assert.Equal(t, http.MethodGet, output.Responses[0].Method)
+		assert.Contains(t, output.Responses[0].URL, uuid.Nil.String())
+		assert.Equal(t, http.MethodPost, output.Responses[1].Method)
+		assert.Equal(t, http.StatusBadRequest, output.Responses[1].StatusCode)
+	}
+
+	assert.Contains(t, output.String(), "< error: ")
+	assert.Contains(t, output.String(), "< 400 Bad Request")
+	assert.NotContains(t, output.String(), "secret")
+}
@@ -0,0 +1,36 @@
+package form3
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// media type sent in the Accept header unless WithAcceptHeader or WithContentType set
+// another one, as the API responds with JSON:API documents
+const defaultAccept = "application/vnd.api+json"
+
+// ResponseDecoder decodes the bodies of the responses of the Form3 API, e.g. to receive
+// MessagePack instead of JSON. Decode must honour the json struct tags of the values it
+// decodes into, as they are the only field names the models declare: a MessagePack
+// decoder would, for instance, configure its library to read json tags.
+type ResponseDecoder interface {
+	// Decode decodes the body read from r into v, a non-nil pointer.
+	Decode(r io.Reader, v interface{}) error
+
+	// ContentType returns the media type of the bodies the decoder reads,
+	// e.g. "application/json".
+	ContentType() string
+}
+
+// JSONDecoder is the default ResponseDecoder, reading JSON bodies.
+type JSONDecoder struct{}
+
+// Decode decodes the JSON body read from r into v.
+func (JSONDecoder) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// ContentType returns "application/json".
+func (JSONDecoder) ContentType() string {
+	return "application/json"
+}
@@ -0,0 +1,142 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingDecoder decodes JSON under a custom media type, counting the bodies it decodes.
+type countingDecoder struct {
+	decoded int
+}
+
+func (d *countingDecoder) Decode(r io.Reader, v interface{}) error {
+	d.decoded++
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (d *countingDecoder) ContentType() string {
+	return "application/vnd.form3+json"
+}
+
+func TestWithResponseDecoder(t *testing.T) {
+	id := uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+	type received struct {
+		accept      string
+		contentType string
+	}
+	var requests []received
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, received{
+			accept:      r.Header.Get("Accept"),
+			contentType: r.Header.Get("Content-Type"),
+		})
+
+		w.Header().Set("Content-Type", "application/vnd.form3+json")
+		if r.Method == http.MethodGet && r.URL.Path == "/v1/organisation/accounts" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"}}`))
+	}))
+	defer ts.Close()
+
+	decoder := &countingDecoder{}
+	client := NewClient(ts.URL, WithContentType(decoder.ContentType()), WithResponseDecoder(decoder))
+
+	fetched, err := client.Fetch(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, fetched.ID)
+
+	listed, err := client.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+
+	created, err := client.Create(context.Background(), newTestAccounts(1)[0])
+	assert.NoError(t, err)
+	assert.Equal(t, id, created.ID)
+
+	assert.Equal(t, 3, decoder.decoded)
+	assert.Equal(t, []received{
+		{accept: "application/vnd.form3+json"},
+		{accept: "application/vnd.form3+json"},
+		{accept: "application/vnd.form3+json", contentType: "application/vnd.form3+json"},
+	}, requests)
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	client := NewClient("http://localhost")
+
+	account := OrganisationAccount{Version: 3}
+	err := client.decodeEnvelope(strings.NewReader(`{"other":{"version":1}}`), "data", &account)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, account.Version, "missing key leaves the value untouched")
+
+	err = client.decodeEnvelope(strings.NewReader(`{"data":{"version":1}}`), "data", &account)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, account.Version)
+
+	err = client.decodeEnvelope(strings.NewReader(`not json`), "data", &account)
+	assert.Error(t, err)
+}
+
+func TestWithAcceptHeader(t *testing.T) {
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		name           string
+		options        []ClientOption
+		expectedAccept string
+		expectedErr    bool
+	}{
+		{
+			name:           "OK - default media type",
+			expectedAccept: "application/vnd.api+json",
+		},
+		{
+			name:           "OK - media type is set",
+			options:        []ClientOption{WithAcceptHeader("application/json; charset=utf-8")},
+			expectedAccept: "application/json; charset=utf-8",
+		},
+		{
+			name:           "OK - media type overrides the content type",
+			options:        []ClientOption{WithContentType("application/msgpack"), WithAcceptHeader("application/json")},
+			expectedAccept: "application/json",
+		},
+		{
+			name:        "Not OK - invalid media type",
+			options:     []ClientOption{WithAcceptHeader("application/json, text/plain")},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			accept = ""
+
+			_, err := NewClient(ts.URL, tc.options...).List(context.Background())
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAccept, accept)
+		})
+	}
+}
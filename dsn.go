@@ -0,0 +1,89 @@
+package form3
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ParseDSN returns a new client configured after the given data source name, e.g.
+//
+//	form3://apikey@api.form3.tech?timeout=5s&maxRetries=3
+//
+// The form3 scheme talks to the API over HTTPS, while form3+http talks to it over
+// plain HTTP, e.g. to reach a local instance. The optional user part is the API key.
+// The supported query parameters are:
+//
+//   - timeout: how long a single attempt may take, e.g. 5s (see WithTimeout)
+//   - maxRetries: how many times a request may be retried (see WithMaxRetries)
+//   - apiVersion: the version of the API to talk to, e.g. v2 (see WithAPIVersion)
+//   - pageSize: the page size of List calls (see WithDefaultPageSize)
+//
+// Any other parameter makes ParseDSN fail rather than being ignored.
+func ParseDSN(dsn string) (*Client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parsing DSN: %w", err)
+	}
+
+	var scheme string
+	switch u.Scheme {
+	case "form3":
+		scheme = "https"
+	case "form3+http":
+		scheme = "http"
+	default:
+		return nil, fmt.Errorf("parsing DSN: unsupported scheme %q, expected form3 or form3+http", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("parsing DSN: missing host")
+	}
+
+	var coo []ClientOption
+
+	if apiKey := u.User.Username(); apiKey != "" {
+		coo = append(coo, WithAPIKey(apiKey))
+	}
+
+	for key, values := range u.Query() {
+		value := values[len(values)-1]
+
+		switch key {
+		case "timeout":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("parsing DSN: timeout=%q is not a positive duration, e.g. 5s", value)
+			}
+			coo = append(coo, WithTimeout(d))
+		case "maxRetries":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("parsing DSN: maxRetries=%q is not a non-negative integer", value)
+			}
+			coo = append(coo, WithMaxRetries(n))
+		case "apiVersion":
+			if value == "" {
+				return nil, fmt.Errorf("parsing DSN: apiVersion is empty")
+			}
+			coo = append(coo, WithAPIVersion(value))
+		case "pageSize":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("parsing DSN: pageSize=%q is not a positive integer", value)
+			}
+			coo = append(coo, WithDefaultPageSize(n))
+		default:
+			return nil, fmt.Errorf("parsing DSN: unsupported parameter %q", key)
+		}
+	}
+
+	baseURL := url.URL{
+		Scheme: scheme,
+		Host:   u.Host,
+		Path:   u.Path,
+	}
+
+	return NewClient(baseURL.String(), coo...), nil
+}
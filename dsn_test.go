@@ -0,0 +1,80 @@
+package form3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDSN(t *testing.T) {
+	testCases := []struct {
+		name               string
+		dsn                string
+		expectedBaseURL    string
+		expectedAPIKey     string
+		expectedTimeout    time.Duration
+		expectedMaxRetries int
+		expectedAPIVersion string
+		expectedPageSize   int
+		expectErr          bool
+	}{
+		{
+			name:               "OK - all parameters",
+			dsn:                "form3://apikey@api.form3.tech?timeout=5s&maxRetries=3&apiVersion=v2&pageSize=50",
+			expectedBaseURL:    "https://api.form3.tech",
+			expectedAPIKey:     "apikey",
+			expectedTimeout:    5 * time.Second,
+			expectedMaxRetries: 3,
+			expectedAPIVersion: "v2",
+			expectedPageSize:   50,
+		},
+		{
+			name:               "OK - plain HTTP without parameters",
+			dsn:                "form3+http://localhost:8080",
+			expectedBaseURL:    "http://localhost:8080",
+			expectedTimeout:    timeout,
+			expectedMaxRetries: -1,
+			expectedAPIVersion: defaultAPIVersion,
+		},
+		{
+			name:      "Not OK - unsupported scheme",
+			dsn:       "http://localhost:8080",
+			expectErr: true,
+		},
+		{
+			name:      "Not OK - unsupported parameter",
+			dsn:       "form3://localhost:8080?retries=3",
+			expectErr: true,
+		},
+		{
+			name:      "Not OK - malformed timeout",
+			dsn:       "form3://localhost:8080?timeout=5",
+			expectErr: true,
+		},
+		{
+			name:      "Not OK - missing host",
+			dsn:       "form3://?timeout=5s",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := ParseDSN(tc.dsn)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedBaseURL, client.baseURL)
+			assert.Equal(t, tc.expectedAPIKey, client.apiKey)
+			assert.Equal(t, tc.expectedTimeout, client.httpClient.load().Timeout)
+			assert.Equal(t, tc.expectedMaxRetries, client.maxRetries)
+			assert.Equal(t, tc.expectedAPIVersion, client.apiVersion)
+			assert.Equal(t, tc.expectedPageSize, client.defaultPageSize)
+		})
+	}
+}
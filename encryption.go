@@ -0,0 +1,151 @@
+package form3
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// ErrDecryptField is returned when a field received from the API cannot be decrypted
+// with any of the keys of the client.
+var ErrDecryptField = errors.New("could not decrypt field")
+
+// fieldEncryptor encrypts attributes of organisation accounts before they are sent
+// to the API and decrypts them once received.
+type fieldEncryptor struct {
+	// fields holds the indexes of the attributes to encrypt inside OrganisationAccountAttributes
+	fields map[string]int
+	// aeads holds the ciphers of the keys, the first one being used to encrypt
+	aeads []cipher.AEAD
+}
+
+// newAEAD returns the AES-GCM cipher of the key, which must be 16, 24 or 32 bytes long.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("field encryption key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// newFieldEncryptor returns an encryptor of the given attributes, named after their JSON
+// tags. Only string attributes, optional or not, can be encrypted.
+func newFieldEncryptor(key []byte, fields []string) (*fieldEncryptor, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkAttributeFields(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string]int, len(fields))
+	attributesType := reflect.TypeOf(OrganisationAccountAttributes{})
+	for i := 0; i < attributesType.NumField(); i++ {
+		field := attributesType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		for _, f := range fields {
+			if f != name {
+				continue
+			}
+
+			t := field.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() != reflect.String {
+				return nil, fmt.Errorf("attribute %q cannot be encrypted, only string attributes can", name)
+			}
+
+			indexes[name] = i
+		}
+	}
+
+	return &fieldEncryptor{fields: indexes, aeads: []cipher.AEAD{aead}}, nil
+}
+
+// encrypt replaces the value of every encrypted attribute that is set with its
+// base64-encoded ciphertext.
+func (e *fieldEncryptor) encrypt(organisationAccount *OrganisationAccount) error {
+	return e.transform(organisationAccount, func(plaintext string) (string, error) {
+		aead := e.aeads[0]
+
+		nonce := make([]byte, aead.NonceSize())
+		_, err := io.ReadFull(rand.Reader, nonce)
+		if err != nil {
+			return "", err
+		}
+
+		return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+	})
+}
+
+// decrypt replaces the value of every encrypted attribute that is set with its plaintext,
+// trying every key from the newest to the oldest.
+func (e *fieldEncryptor) decrypt(organisationAccount *OrganisationAccount) error {
+	return e.transform(organisationAccount, func(encoded string) (string, error) {
+		ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "", ErrDecryptField
+		}
+
+		for _, aead := range e.aeads {
+			if len(ciphertext) < aead.NonceSize() {
+				continue
+			}
+
+			nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+			plaintext, err := aead.Open(nil, nonce, sealed, nil)
+			if err == nil {
+				return string(plaintext), nil
+			}
+		}
+
+		return "", ErrDecryptField
+	})
+}
+
+// transform applies fn to the value of every encrypted attribute that is set.
+func (e *fieldEncryptor) transform(organisationAccount *OrganisationAccount, fn func(string) (string, error)) error {
+	attributes := reflect.ValueOf(&organisationAccount.Attributes).Elem()
+
+	for name, i := range e.fields {
+		field := attributes.Field(i)
+
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+
+			// do not change the string the caller points to
+			value, err := fn(field.Elem().String())
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			field.Set(reflect.ValueOf(&value))
+			continue
+		}
+
+		if field.String() == "" {
+			continue
+		}
+
+		value, err := fn(field.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		field.SetString(value)
+	}
+
+	return nil
+}
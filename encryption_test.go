@@ -0,0 +1,129 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newStoringServer returns a test server that stores the organisation accounts it is
+// sent as they are, and serves them back.
+func newStoringServer(t *testing.T) (*httptest.Server, map[uuid.UUID]json.RawMessage) {
+	var mu sync.Mutex
+	stored := map[uuid.UUID]json.RawMessage{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Method == http.MethodPost {
+			var body struct {
+				Data json.RawMessage `json:"data"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+			var organisationAccount OrganisationAccount
+			assert.NoError(t, json.Unmarshal(body.Data, &organisationAccount))
+			stored[organisationAccount.ID] = body.Data
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&body)
+			return
+		}
+
+		id := uuid.MustParse(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		_ = json.NewEncoder(w).Encode(map[string]json.RawMessage{"data": stored[id]})
+	})), stored
+}
+
+func TestWithFieldEncryption(t *testing.T) {
+	ts, stored := newStoringServer(t)
+	defer ts.Close()
+
+	oldKey := []byte(strings.Repeat("k", 16))
+	newKey := []byte(strings.Repeat("n", 32))
+	ctx := context.Background()
+
+	organisationAccount := OrganisationAccount{
+		ID: uuid.New(),
+		Attributes: OrganisationAccountAttributes{
+			BankID:        "400300",
+			AccountNumber: String("41426819"),
+			IBAN:          String("GB29NWBK60161331926819"),
+		},
+	}
+
+	client := NewClient(ts.URL, WithFieldEncryption(oldKey, "account_number", "iban", "bank_id"))
+
+	created, err := client.Create(ctx, organisationAccount)
+	assert.NoError(t, err)
+	assert.Equal(t, organisationAccount, created)
+
+	// the API only sees ciphertexts, while the caller's account is left untouched
+	assert.NotContains(t, string(stored[organisationAccount.ID]), "41426819")
+	assert.NotContains(t, string(stored[organisationAccount.ID]), "400300")
+	assert.Equal(t, "41426819", *organisationAccount.Attributes.AccountNumber)
+
+	fetched, err := client.Fetch(ctx, organisationAccount.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, organisationAccount, fetched)
+
+	// after rotating the key, accounts encrypted with the old one can still be read
+	rotated := NewClient(ts.URL, WithFieldEncryption(oldKey, "account_number", "iban", "bank_id"), WithFieldEncryptionKey(newKey))
+
+	fetched, err = rotated.Fetch(ctx, organisationAccount.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, organisationAccount, fetched)
+
+	rotatedAccount := OrganisationAccount{ID: uuid.New(), Attributes: OrganisationAccountAttributes{BankID: "400300"}}
+	_, err = rotated.Create(ctx, rotatedAccount)
+	assert.NoError(t, err)
+
+	// while accounts encrypted with the new key cannot be read with the old one only
+	_, err = client.Fetch(ctx, rotatedAccount.ID)
+	assert.True(t, errors.Is(err, ErrDecryptField))
+}
+
+func TestWithFieldEncryptionInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		coo  []ClientOption
+	}{
+		{
+			name: "key of invalid length",
+			coo:  []ClientOption{WithFieldEncryption([]byte("short"), "iban")},
+		},
+		{
+			name: "unknown attribute",
+			coo:  []ClientOption{WithFieldEncryption(make([]byte, 16), "colour")},
+		},
+		{
+			name: "non-string attribute",
+			coo:  []ClientOption{WithFieldEncryption(make([]byte, 16), "joint_account")},
+		},
+		{
+			name: "key rotation without encryption",
+			coo:  []ClientOption{WithFieldEncryptionKey(make([]byte, 16))},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient("http://localhost", tc.coo...)
+
+			_, err := client.Create(context.Background(), OrganisationAccount{})
+			assert.Error(t, err)
+
+			_, err = client.Fetch(context.Background(), uuid.New())
+			assert.Error(t, err)
+		})
+	}
+}
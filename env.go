@@ -0,0 +1,61 @@
+package form3
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// names of the environment variables read by NewClientFromEnv
+const (
+	envBaseURL    = "FORM3_BASE_URL"
+	envAPIKey     = "FORM3_API_KEY"
+	envTimeout    = "FORM3_TIMEOUT"
+	envMaxRetries = "FORM3_MAX_RETRIES"
+)
+
+// NewClientFromEnv returns a new client configured through the environment:
+//
+//   - FORM3_BASE_URL (required): the base URL of the Form3 API
+//   - FORM3_API_KEY: the API key to authenticate requests with
+//   - FORM3_TIMEOUT: how long a single attempt may take, as a duration, e.g. "30s" (10s if not set)
+//   - FORM3_MAX_RETRIES: how many times a request may be retried (bounded only by time if not set)
+//
+// The given client options are applied after the environment, thus take precedence over it.
+func NewClientFromEnv(coo ...ClientOption) (*Client, error) {
+	baseURL := os.Getenv(envBaseURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("%s is not set", envBaseURL)
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%s=%q is not an absolute URL", envBaseURL, baseURL)
+	}
+
+	var envOptions []ClientOption
+
+	if apiKey := os.Getenv(envAPIKey); apiKey != "" {
+		envOptions = append(envOptions, WithAPIKey(apiKey))
+	}
+
+	if v := os.Getenv(envTimeout); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%s=%q is not a positive duration, e.g. 30s", envTimeout, v)
+		}
+		envOptions = append(envOptions, WithTimeout(d))
+	}
+
+	if v := os.Getenv(envMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s=%q is not a non-negative integer", envMaxRetries, v)
+		}
+		envOptions = append(envOptions, WithMaxRetries(n))
+	}
+
+	return NewClient(baseURL, append(envOptions, coo...)...), nil
+}
@@ -0,0 +1,102 @@
+package form3
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	testCases := []struct {
+		name               string
+		env                map[string]string
+		coo                []ClientOption
+		expectedTimeout    time.Duration
+		expectedMaxRetries int
+		expectedAPIKey     string
+		expectedErr        bool
+	}{
+		{
+			name:               "defaults",
+			env:                map[string]string{envBaseURL: "http://localhost:8080"},
+			expectedTimeout:    timeout,
+			expectedMaxRetries: -1,
+		},
+		{
+			name: "all variables set",
+			env: map[string]string{
+				envBaseURL:    "http://localhost:8080",
+				envAPIKey:     "secret",
+				envTimeout:    "30s",
+				envMaxRetries: "3",
+			},
+			expectedTimeout:    30 * time.Second,
+			expectedMaxRetries: 3,
+			expectedAPIKey:     "secret",
+		},
+		{
+			name: "options override environment",
+			env: map[string]string{
+				envBaseURL: "http://localhost:8080",
+				envTimeout: "30s",
+			},
+			coo:                []ClientOption{WithTimeout(time.Second)},
+			expectedTimeout:    time.Second,
+			expectedMaxRetries: -1,
+		},
+		{
+			name:        "missing base URL",
+			env:         map[string]string{},
+			expectedErr: true,
+		},
+		{
+			name:        "relative base URL",
+			env:         map[string]string{envBaseURL: "localhost"},
+			expectedErr: true,
+		},
+		{
+			name:        "malformed timeout",
+			env:         map[string]string{envBaseURL: "http://localhost:8080", envTimeout: "30"},
+			expectedErr: true,
+		},
+		{
+			name:        "negative max retries",
+			env:         map[string]string{envBaseURL: "http://localhost:8080", envMaxRetries: "-1"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, key := range []string{envBaseURL, envAPIKey, envTimeout, envMaxRetries} {
+				setenv(t, key, tc.env[key])
+			}
+
+			client, err := NewClientFromEnv(tc.coo...)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTimeout, client.httpClient.load().Timeout)
+			assert.Equal(t, tc.expectedMaxRetries, client.maxRetries)
+			assert.Equal(t, tc.expectedAPIKey, client.apiKey)
+		})
+	}
+}
+
+// setenv sets the environment variable for the duration of the test.
+func setenv(t *testing.T, key, value string) {
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrBadRequest can be used with errors.Is to check whether the Form3 API
+	// responded with 400 Bad Request.
+	ErrBadRequest = &APIError{StatusCode: http.StatusBadRequest}
+
+	// ErrNotFound can be used with errors.Is to check whether the Form3 API
+	// responded with 404 Not Found.
+	ErrNotFound = &APIError{StatusCode: http.StatusNotFound}
+
+	// ErrConflict can be used with errors.Is to check whether the Form3 API
+	// responded with 409 Conflict, e.g. because of an incorrect version.
+	ErrConflict = &APIError{StatusCode: http.StatusConflict}
+
+	// ErrUnknownField is returned when a request option names an attribute that
+	// OrganisationAccountAttributes does not have.
+	ErrUnknownField = errors.New("unknown organisation account attribute")
+
+	// ErrBodyTooLargeToBuffer is returned when the body of a request cannot be rewound to
+	// be sent again on retries, and is larger than the client accepts to buffer in memory.
+	ErrBodyTooLargeToBuffer = errors.New("request body too large to buffer")
+
+	// ErrStaleData is wrapped by the error returned along with a stale result, when
+	// the client is created with WithStaleOnError and the API is unavailable.
+	ErrStaleData = errors.New("stale data")
+
+	// ErrUnsupportedAPIVersion is returned when the API reports a version that is not
+	// compatible with SupportedAPIVersion.
+	ErrUnsupportedAPIVersion = errors.New("unsupported API version")
+
+	// ErrConflictingListOptions is returned by List when it is given
+	// list options that contradict each other.
+	ErrConflictingListOptions = errors.New("conflicting list options")
+
+	// ErrListGuardExceeded is returned by List calls without any filter that return
+	// more accounts than allowed by WithListGuard.
+	ErrListGuardExceeded = errors.New("list guard exceeded")
+
+	// ErrMaxResultsExceeded is returned by the calls paging through organisation
+	// accounts once they collected more accounts than allowed by WithMaxListResults.
+	ErrMaxResultsExceeded = errors.New("max list results exceeded")
+
+	// ErrClientClosed is returned by the methods of a client once CancelInflight or
+	// Close was called, including by the requests they cancelled.
+	ErrClientClosed = errors.New("client closed")
+)
+
+// APIError is returned whenever the Form3 API responds with an unsuccessful
+// status code. It carries both the status code and the error message sent
+// back by the API.
+type APIError struct {
+	StatusCode   int
+	ErrorMessage string
+	// RetryAfter is how long the API asked the client to wait before retrying,
+	// as sent in the Retry-After header of the response (zero if absent).
+	RetryAfter time.Duration
+	// Header holds the headers of the response, nil if the error was not built
+	// from a response.
+	Header http.Header
+}
+
+// Error returns the message sent by the API or, if the API did not send
+// one, the text of the status code.
+func (e *APIError) Error() string {
+	if e.ErrorMessage == "" {
+		return http.StatusText(e.StatusCode)
+	}
+
+	return e.ErrorMessage
+}
+
+// Is reports whether target is an *APIError with the same status code, which
+// makes checks such as errors.Is(err, ErrNotFound) possible.
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+
+	return e.StatusCode == t.StatusCode
+}
+
+// RetryError is returned when a request keeps failing with a retriable status, such as
+// 429 Too Many Requests, or with a network error retried by WithRetryOnNetworkError, until
+// the back-off gives up. It wraps the *APIError or the network error of the last attempt,
+// thus errors.Is and errors.As see through it.
+type RetryError struct {
+	// Err is the error of the last attempt.
+	Err error
+	// Attempts is the number of attempts made, including the first one.
+	Attempts int
+	// TotalElapsed is the time spent on the request, across all its attempts.
+	TotalElapsed time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts in %s: %s", e.Attempts, e.TotalElapsed, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// parseRetryAfter returns the duration sent in a Retry-After header, which can
+// be either a number of seconds or an HTTP date. It returns zero if the header
+// is absent or invalid.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// isEOF reports whether err is caused by the connection being closed before the
+// response was read, e.g. by a load balancer dropping an idle keep-alive connection.
+func isEOF(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isConnectionReset reports whether err is caused by the peer resetting the connection.
+func isConnectionReset(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && errors.Is(opErr.Err, syscall.ECONNRESET)
+}
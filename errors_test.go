@@ -0,0 +1,50 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{
+			name:     "OK - same status code",
+			err:      &APIError{StatusCode: http.StatusNotFound, ErrorMessage: "does not exist"},
+			target:   ErrNotFound,
+			expected: true,
+		},
+		{
+			name:     "OK - wrapped error",
+			err:      fmt.Errorf("deleting account: %w", &APIError{StatusCode: http.StatusConflict}),
+			target:   ErrConflict,
+			expected: true,
+		},
+		{
+			name:     "Not OK - different status code",
+			err:      &APIError{StatusCode: http.StatusBadRequest},
+			target:   ErrNotFound,
+			expected: false,
+		},
+		{
+			name:     "Not OK - not an API error",
+			err:      errors.New("bad request"),
+			target:   ErrBadRequest,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, errors.Is(tc.err, tc.target))
+		})
+	}
+}
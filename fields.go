@@ -0,0 +1,91 @@
+package form3
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// attributeFields holds the JSON names of the fields of OrganisationAccountAttributes.
+var attributeFields = jsonFieldNames(reflect.TypeOf(OrganisationAccountAttributes{}))
+
+// jsonFieldNames returns the set of JSON names of the fields of the given struct type.
+func jsonFieldNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// accountFields holds the JSON names of the fields of OrganisationAccount.
+var accountFields = jsonFieldNames(reflect.TypeOf(OrganisationAccount{}))
+
+// checkSelectableFields returns an error wrapping ErrUnknownField if any of the given
+// names is neither the JSON name of a field of an account nor of an attribute.
+func checkSelectableFields(fields []string) error {
+	for _, field := range fields {
+		_, isAccountField := accountFields[field]
+		_, isAttributeField := attributeFields[field]
+		if !isAccountField && !isAttributeField {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+	}
+	return nil
+}
+
+// checkAttributeFields returns an error wrapping ErrUnknownField if any of the given
+// names is not the JSON name of an attribute.
+func checkAttributeFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := attributeFields[field]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+	}
+	return nil
+}
+
+// marshalAccount encodes the organisation account, leaving out the attributes that
+// the request options do not select.
+func marshalAccount(organisationAccount OrganisationAccount, options requestOptions) (json.RawMessage, error) {
+	if len(options.includeFields) == 0 && len(options.excludeFields) == 0 {
+		return json.Marshal(organisationAccount)
+	}
+
+	attributesBytes, err := json.Marshal(organisationAccount.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]json.RawMessage
+	err = json.Unmarshal(attributesBytes, &attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options.includeFields) > 0 {
+		included := make(map[string]json.RawMessage, len(options.includeFields))
+		for _, field := range options.includeFields {
+			if value, ok := attributes[field]; ok {
+				included[field] = value
+			}
+		}
+		attributes = included
+	}
+
+	for _, field := range options.excludeFields {
+		delete(attributes, field)
+	}
+
+	return json.Marshal(struct {
+		OrganisationAccount
+		Attributes map[string]json.RawMessage `json:"attributes"`
+	}{
+		OrganisationAccount: organisationAccount,
+		Attributes:          attributes,
+	})
+}
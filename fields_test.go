@@ -0,0 +1,143 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldSelection(t *testing.T) {
+	organisationAccount := OrganisationAccount{
+		ID:      uuid.New(),
+		Version: 1,
+		Attributes: OrganisationAccountAttributes{
+			Country:      CountryGB,
+			BaseCurrency: "GBP",
+			Name:         []string{"Jane Doe"},
+			BIC:          String("NWBKGB22"),
+		},
+	}
+
+	testCases := []struct {
+		name               string
+		roo                []RequestOption
+		expectedAttributes []string
+		expectedErr        error
+	}{
+		{
+			name:               "include fields",
+			roo:                []RequestOption{IncludeFields("name", "bic")},
+			expectedAttributes: []string{"name", "bic"},
+		},
+		{
+			name:               "exclude fields",
+			roo:                []RequestOption{IncludeFields("name", "bic", "country"), ExcludeFields("country")},
+			expectedAttributes: []string{"name", "bic"},
+		},
+		{
+			name:        "unknown field",
+			roo:         []RequestOption{IncludeFields("colour")},
+			expectedErr: ErrUnknownField,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPatch, r.Method)
+				assert.Equal(t, "/v1/organisation/accounts/"+organisationAccount.ID.String(), r.URL.Path)
+
+				var body struct {
+					Data struct {
+						ID         uuid.UUID                  `json:"id"`
+						Version    int                        `json:"version"`
+						Attributes map[string]json.RawMessage `json:"attributes"`
+					} `json:"data"`
+				}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				assert.Equal(t, organisationAccount.ID, body.Data.ID)
+				assert.Equal(t, 1, body.Data.Version)
+
+				var attributes []string
+				for name := range body.Data.Attributes {
+					attributes = append(attributes, name)
+				}
+				assert.ElementsMatch(t, tc.expectedAttributes, attributes)
+
+				_, _ = w.Write([]byte(`{"data":{"version":2}}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(ts.URL)
+
+			updated, err := client.Update(context.Background(), organisationAccount, tc.roo...)
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, updated.Version)
+		})
+	}
+}
+
+func TestPrefer(t *testing.T) {
+	organisationAccount := OrganisationAccount{ID: uuid.New(), Type: AccountType}
+
+	testCases := []struct {
+		name           string
+		roo            []RequestOption
+		expectedPrefer string
+		statusCode     int
+		body           string
+		expected       OrganisationAccount
+	}{
+		{
+			name:           "minimal response",
+			roo:            []RequestOption{WithPreferMinimal()},
+			expectedPrefer: "return=minimal",
+			statusCode:     http.StatusNoContent,
+			expected:       organisationAccount,
+		},
+		{
+			name:           "asynchronous processing",
+			roo:            []RequestOption{WithPreferAsync(), WithPreferMinimal()},
+			expectedPrefer: "respond-async, return=minimal",
+			statusCode:     http.StatusAccepted,
+			expected:       organisationAccount,
+		},
+		{
+			name:           "preference not honoured",
+			roo:            []RequestOption{WithPreferMinimal()},
+			expectedPrefer: "return=minimal",
+			statusCode:     http.StatusCreated,
+			body:           `{"data":{"version":1}}`,
+			expected:       OrganisationAccount{Version: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedPrefer, r.Header.Get("Prefer"))
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer ts.Close()
+
+			client := NewClient(ts.URL)
+
+			created, err := client.Create(context.Background(), organisationAccount, tc.roo...)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, created)
+		})
+	}
+}
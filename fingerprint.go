@@ -0,0 +1,78 @@
+package form3
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Fingerprint returns the hex-encoded SHA-256 digest of the account encoded as
+// canonical JSON, i.e. with sorted keys and no whitespace, so that two accounts
+// have the same fingerprint if and only if they hold the same data. It can be
+// stored along with the account to later detect changes made to it.
+func (o OrganisationAccount) Fingerprint() (string, error) {
+	b, err := canonicalJSON(o)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Equal reports whether o and other hold the same data, i.e. whether they have the
+// same fingerprint. See OrganisationAccount.Fingerprint.
+func (o OrganisationAccount) Equal(other OrganisationAccount) bool {
+	a, err := canonicalJSON(o)
+	if err != nil {
+		return false
+	}
+
+	b, err := canonicalJSON(other)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(a, b)
+}
+
+// AccountsFingerprint returns the fingerprint of a set of accounts, which does not
+// depend on the order they are given in. See OrganisationAccount.Fingerprint.
+func AccountsFingerprint(accounts []OrganisationAccount) (string, error) {
+	sorted := make([]OrganisationAccount, len(accounts))
+	copy(sorted, accounts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].ID[:], sorted[j].ID[:]) < 0
+	})
+
+	b, err := canonicalJSON(sorted)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON encodes v as JSON with the keys of every object sorted.
+func canonicalJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// maps are encoded with sorted keys, while numbers are kept as they were encoded
+	var generic interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&generic)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(generic)
+}
@@ -0,0 +1,81 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	account := OrganisationAccount{
+		ID:      uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		Type:    AccountType,
+		Version: 1,
+		Attributes: OrganisationAccountAttributes{
+			Country: CountryGB,
+			Name:    []string{"Jane Doe"},
+		},
+	}
+
+	fingerprint, err := account.Fingerprint()
+	assert.NoError(t, err)
+	assert.Len(t, fingerprint, 64)
+
+	same, err := account.Fingerprint()
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint, same)
+
+	changed := account
+	changed.Version = 2
+	other, err := changed.Fingerprint()
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, other)
+}
+
+func TestAccountsFingerprint(t *testing.T) {
+	first := OrganisationAccount{ID: uuid.New()}
+	second := OrganisationAccount{ID: uuid.New()}
+
+	fingerprint, err := AccountsFingerprint([]OrganisationAccount{first, second})
+	assert.NoError(t, err)
+
+	reversed, err := AccountsFingerprint([]OrganisationAccount{second, first})
+	assert.NoError(t, err)
+	assert.Equal(t, fingerprint, reversed)
+
+	single, err := AccountsFingerprint([]OrganisationAccount{first})
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, single)
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	b, err := canonicalJSON(struct {
+		B int               `json:"b"`
+		A map[string]string `json:"a"`
+	}{B: 1, A: map[string]string{"y": "1", "x": "2"}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":{"x":"2","y":"1"},"b":1}`, string(b))
+}
+
+func TestOrganisationAccountEqual(t *testing.T) {
+	account := OrganisationAccount{
+		ID:      uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		Type:    AccountType,
+		Version: 1,
+		Attributes: OrganisationAccountAttributes{
+			Country: "GB",
+			BIC:     String("NWBKGB22"),
+		},
+	}
+
+	same := account
+	same.Attributes.BIC = String("NWBKGB22")
+	assert.True(t, account.Equal(same))
+
+	changed := account
+	changed.Attributes.BIC = String("BARCGB22")
+	assert.False(t, account.Equal(changed))
+	assert.False(t, account.Equal(OrganisationAccount{}))
+}
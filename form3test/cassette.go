@@ -0,0 +1,186 @@
+// Package form3test contains helpers for testing code that uses the form3 client library.
+package form3test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Cassette is an http.RoundTripper that records HTTP interactions to a JSON file,
+// or replays interactions previously recorded to that file. It is meant to be passed
+// to form3.WithHTTPTransport, so that tests can run without a live Form3 API.
+type Cassette struct {
+	path string
+	real http.RoundTripper
+
+	mu           sync.Mutex
+	loaded       bool
+	interactions []Interaction
+	replayed     []bool
+}
+
+// Interaction is a single request and its response, as stored inside a cassette file.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request used to match it when replaying.
+// The URL only contains the path and query, so that cassettes do not depend on
+// the host of the server they were recorded against.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as stored inside a cassette file.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// NewRecordingTransport returns a transport that performs requests using real and
+// records every interaction to cassettePath, overwriting any previous recording.
+func NewRecordingTransport(cassettePath string, real http.RoundTripper) http.RoundTripper {
+	return &Cassette{
+		path:   cassettePath,
+		real:   real,
+		loaded: true,
+	}
+}
+
+// NewReplayTransport returns a transport that never performs requests, but instead
+// answers them with the interactions recorded in cassettePath. Each recorded interaction
+// is replayed at most once, in the order it was recorded.
+func NewReplayTransport(cassettePath string) http.RoundTripper {
+	return &Cassette{
+		path: cassettePath,
+	}
+}
+
+// RoundTrip records or replays a single interaction.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordedReq, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.real == nil {
+		return c.replay(req, recordedReq)
+	}
+
+	return c.record(req, recordedReq)
+}
+
+// record performs the request and appends the interaction to the cassette file.
+func (c *Cassette) record(req *http.Request, recordedReq RecordedRequest) (*http.Response, error) {
+	resp, err := c.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.interactions = append(c.interactions, Interaction{
+		Request: recordedReq,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       string(body),
+		},
+	})
+
+	f, err := os.Create(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "\t")
+
+	err = encoder.Encode(c.interactions)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// replay answers the request with the first matching interaction that was not replayed yet.
+func (c *Cassette) replay(req *http.Request, recordedReq RecordedRequest) (*http.Response, error) {
+	if !c.loaded {
+		f, err := os.Open(c.path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		err = json.NewDecoder(f).Decode(&c.interactions)
+		if err != nil {
+			return nil, err
+		}
+
+		c.replayed = make([]bool, len(c.interactions))
+		c.loaded = true
+	}
+
+	for i, interaction := range c.interactions {
+		if c.replayed[i] || interaction.Request != recordedReq {
+			continue
+		}
+
+		c.replayed[i] = true
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode: interaction.Response.StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     interaction.Response.Header,
+			Body:       ioutil.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("form3test: no recorded interaction for %s %s", recordedReq.Method, recordedReq.URL)
+}
+
+// recordRequest reads the parts of the request stored in a cassette, leaving
+// the request body intact so the request can still be sent.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recordedReq := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+	}
+
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return RecordedRequest{}, err
+		}
+		req.Body.Close()
+
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		recordedReq.Body = string(body)
+	}
+
+	return recordedReq, nil
+}
@@ -0,0 +1,45 @@
+package form3test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nclandrei/form3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCassette(t *testing.T) {
+	accountID := uuid.New()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"` + accountID.String() + `","type":"accounts"}}`))
+	}))
+
+	cassettePath := filepath.Join(t.TempDir(), "fetch.json")
+
+	recordingClient := form3.NewClient(
+		ts.URL,
+		form3.WithHTTPTransport(NewRecordingTransport(cassettePath, http.DefaultTransport)),
+	)
+
+	recorded, err := recordingClient.Fetch(context.Background(), accountID)
+	assert.NoError(t, err)
+
+	ts.Close()
+
+	replayingClient := form3.NewClient(
+		"http://form3.invalid",
+		form3.WithHTTPTransport(NewReplayTransport(cassettePath)),
+	)
+
+	replayed, err := replayingClient.Fetch(context.Background(), accountID)
+	assert.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+
+	_, err = replayingClient.Fetch(context.Background(), accountID)
+	assert.Error(t, err)
+}
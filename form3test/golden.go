@@ -0,0 +1,44 @@
+package form3test
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/nclandrei/form3"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGolden is set by passing -form3test.update to go test, in which case
+// AssertGolden overwrites the golden files instead of comparing against them. The
+// flag is namespaced so as not to clash with an -update flag of the importing tests.
+var UpdateGolden = flag.Bool("form3test.update", false, "update the golden files used by form3test.AssertGolden")
+
+// AssertGolden serialises got to indented JSON and compares it with the content of
+// goldenFile, failing the test if they differ. When the -form3test.update flag is
+// passed, goldenFile is overwritten with the JSON of got instead.
+func AssertGolden(t testing.TB, got form3.OrganisationAccount, goldenFile string) {
+	t.Helper()
+
+	gotJSON, err := json.MarshalIndent(got, "", "\t")
+	if err != nil {
+		t.Fatalf("form3test: could not marshal organisation account: %v", err)
+	}
+	gotJSON = append(gotJSON, '\n')
+
+	if *UpdateGolden {
+		err := ioutil.WriteFile(goldenFile, gotJSON, 0644)
+		if err != nil {
+			t.Fatalf("form3test: could not update golden file: %v", err)
+		}
+		return
+	}
+
+	wantJSON, err := ioutil.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("form3test: could not read golden file (run with -form3test.update to create it): %v", err)
+	}
+
+	assert.JSONEq(t, string(wantJSON), string(gotJSON), "organisation account differs from %s", goldenFile)
+}
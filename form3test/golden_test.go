@@ -0,0 +1,30 @@
+package form3test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nclandrei/form3"
+)
+
+func TestAssertGolden(t *testing.T) {
+	account := form3.OrganisationAccount{
+		ID:             uuid.MustParse("a9e3b971-a241-4930-a09f-a7c04bf394fe"),
+		Type:           "accounts",
+		OrganisationID: uuid.MustParse("b6eca650-cf86-4715-b312-fcef0fec1506"),
+		Attributes: form3.OrganisationAccountAttributes{
+			Country:               "GB",
+			BaseCurrency:          "GBP",
+			AccountNumber:         form3.String("41426819"),
+			BankID:                "400300",
+			BankIDCode:            "GBDSC",
+			BIC:                   form3.String("NWBKGB22"),
+			IBAN:                  form3.String("GB11NWBK40030041426819"),
+			Name:                  []string{"Jane Doe"},
+			AccountClassification: form3.ClassificationPersonal,
+			JointAccount:          form3.Bool(false),
+		},
+	}
+
+	AssertGolden(t, account, "testdata/account.golden")
+}
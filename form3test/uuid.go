@@ -0,0 +1,33 @@
+package form3test
+
+import (
+	"encoding/binary"
+
+	"github.com/google/uuid"
+)
+
+// UUIDFromInt returns a deterministic UUID derived from n, so that test cases and
+// fixtures can refer to accounts by a small number instead of an opaque UUID.
+// n is stored in the last bytes of the UUID, thus UUIDFromInt(1) is
+// 00000000-0000-4000-8000-000000000001. The version and variant bits are set
+// as for a random (V4) UUID, which leaves room for any n below 2^56.
+func UUIDFromInt(n int) uuid.UUID {
+	var u uuid.UUID
+
+	binary.BigEndian.PutUint64(u[8:], uint64(n))
+	u[6] = 0x40
+	u[8] = u[8]&0x3f | 0x80
+
+	return u
+}
+
+// MustNewUUID returns a new random UUID, which is never uuid.Nil. It panics if
+// no randomness is available, which is of no concern in tests.
+func MustNewUUID() uuid.UUID {
+	u, err := uuid.NewRandom()
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
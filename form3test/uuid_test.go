@@ -0,0 +1,20 @@
+package form3test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDFromInt(t *testing.T) {
+	assert.Equal(t, "00000000-0000-4000-8000-000000000001", UUIDFromInt(1).String())
+	assert.Equal(t, "00000000-0000-4000-8000-0000000003e8", UUIDFromInt(1000).String())
+	assert.Equal(t, UUIDFromInt(42), UUIDFromInt(42))
+	assert.Equal(t, uint8(4), uint8(UUIDFromInt(42).Version()))
+}
+
+func TestMustNewUUID(t *testing.T) {
+	assert.NotEqual(t, uuid.Nil, MustNewUUID())
+	assert.NotEqual(t, MustNewUUID(), MustNewUUID())
+}
@@ -0,0 +1,87 @@
+package form3
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ibanLengths contains the length of the IBANs of the supported countries that use them.
+var ibanLengths = map[Country]int{
+	CountryBE: 16,
+	CountryCH: 21,
+	CountryDE: 22,
+	CountryES: 24,
+	CountryFR: 27,
+	CountryGB: 22,
+	CountryGR: 27,
+	CountryIT: 27,
+	CountryLU: 20,
+	CountryNL: 18,
+	CountryPL: 28,
+	CountryPT: 25,
+}
+
+// GenerateIBAN builds a valid IBAN for the given country out of the bank ID and the
+// account number, computing its MOD-97 check digits. bankID is the part identifying
+// the bank as it appears inside the IBAN (for GB, the bank code of the BIC followed
+// by the sort code). The account number is padded with leading zeros to fill the
+// country-specific IBAN length.
+//
+// It returns an error if the country does not use IBANs, if the fields are too long
+// or if they contain non-alphanumeric characters.
+func GenerateIBAN(country Country, bankID, accountNumber string) (string, error) {
+	length, ok := ibanLengths[country]
+	if !ok {
+		return "", fmt.Errorf("country %q does not use IBANs", country)
+	}
+
+	bankID = strings.ToUpper(bankID)
+	accountNumber = strings.ToUpper(accountNumber)
+
+	if !isAlphanumeric(bankID) || !isAlphanumeric(accountNumber) {
+		return "", fmt.Errorf("bank ID and account number must only contain letters and digits")
+	}
+
+	bbanLength := length - 4
+	if len(bankID)+len(accountNumber) > bbanLength {
+		return "", fmt.Errorf("bank ID and account number must be at most %d characters long for country %s", bbanLength, country)
+	}
+
+	bban := bankID + strings.Repeat("0", bbanLength-len(bankID)-len(accountNumber)) + accountNumber
+
+	checkDigits := 98 - ibanMod97(bban+string(country)+"00")
+
+	return fmt.Sprintf("%s%02d%s", country, checkDigits, bban), nil
+}
+
+// ibanMod97 computes the remainder of the division by 97 of s, where each
+// letter is replaced by two digits (A = 10, B = 11, ..., Z = 35).
+func ibanMod97(s string) int {
+	var mod int
+	for _, r := range s {
+		var digits string
+		if r >= 'A' && r <= 'Z' {
+			digits = strconv.Itoa(int(r-'A') + 10)
+		} else {
+			digits = string(r)
+		}
+
+		for _, d := range digits {
+			mod = (mod*10 + int(d-'0')) % 97
+		}
+	}
+
+	return mod
+}
+
+// isAlphanumeric reports whether s only contains ASCII uppercase letters and digits.
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,74 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateIBAN(t *testing.T) {
+	testCases := []struct {
+		name          string
+		country       Country
+		bankID        string
+		accountNumber string
+		expectedIBAN  string
+		expectErr     bool
+	}{
+		{
+			name:          "OK - GB IBAN",
+			country:       CountryGB,
+			bankID:        "NWBK601613",
+			accountNumber: "31926819",
+			expectedIBAN:  "GB29NWBK60161331926819",
+		},
+		{
+			name:          "OK - DE IBAN",
+			country:       CountryDE,
+			bankID:        "37040044",
+			accountNumber: "0532013000",
+			expectedIBAN:  "DE89370400440532013000",
+		},
+		{
+			name:          "OK - account number is padded",
+			country:       CountryDE,
+			bankID:        "37040044",
+			accountNumber: "532013000",
+			expectedIBAN:  "DE89370400440532013000",
+		},
+		{
+			name:          "Not OK - country without IBANs",
+			country:       CountryUS,
+			bankID:        "021000021",
+			accountNumber: "123456789",
+			expectErr:     true,
+		},
+		{
+			name:          "Not OK - fields too long",
+			country:       CountryDE,
+			bankID:        "37040044",
+			accountNumber: "05320130001",
+			expectErr:     true,
+		},
+		{
+			name:          "Not OK - non-alphanumeric characters",
+			country:       CountryGB,
+			bankID:        "NWBK40-03-00",
+			accountNumber: "41426819",
+			expectErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			iban, err := GenerateIBAN(tc.country, tc.bankID, tc.accountNumber)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedIBAN, iban)
+			}
+		})
+	}
+}
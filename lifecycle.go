@@ -0,0 +1,125 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// how long Close waits for the goroutines of the client to exit
+var closeTimeout = 5 * time.Second
+
+// lifecycle lets a client cancel all its in-flight requests at once and wait for the
+// goroutines it spawned to exit.
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// mu guards closed, so that no goroutine is added to wg once Close waits for it
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// newLifecycle returns the lifecycle of an open client.
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// isClosed reports whether CancelInflight or Close was called.
+func (l *lifecycle) isClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.closed
+}
+
+// close cancels the context bound to in-flight requests.
+func (l *lifecycle) close() {
+	l.mu.Lock()
+	l.closed = true
+	l.mu.Unlock()
+
+	l.cancel()
+}
+
+// start registers a goroutine that Close waits for, returning false if the client is
+// closed already, in which case it must not be registered.
+func (l *lifecycle) start() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return false
+	}
+	l.wg.Add(1)
+
+	return true
+}
+
+// done unregisters a goroutine registered with start.
+func (l *lifecycle) done() {
+	l.wg.Done()
+}
+
+// bind returns a copy of ctx that is also cancelled once the client is closed. The
+// returned cancel function must be called to release it.
+func (l *lifecycle) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		select {
+		case <-l.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// CancelInflight cancels all the requests the client is performing, and makes any later
+// call to its methods return ErrClientClosed, e.g. when the service using it shuts down.
+// Requests cancelled this way return ErrClientClosed as well.
+func (c *Client) CancelInflight() {
+	c.lifecycle.close()
+}
+
+// Close cancels all the requests the client is performing just like CancelInflight,
+// then waits for the goroutines spawned by the client, e.g. to send the accounts
+// returned by ListIter, and for the calls to ParallelCreate and Watch to return.
+// It returns an error if they are still running after 5 seconds.
+func (c *Client) Close() error {
+	c.lifecycle.close()
+
+	exited := make(chan struct{})
+	go func() {
+		c.lifecycle.wg.Wait()
+		close(exited)
+	}()
+
+	timer := time.NewTimer(closeTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-exited:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("closing client: goroutines still running after %s", closeTimeout)
+	}
+}
+
+// cancelReadCloser calls cancel once closed, releasing the context of the request
+// whose response body it reads.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelReadCloser) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
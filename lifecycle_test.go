@@ -0,0 +1,107 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelInflight(t *testing.T) {
+	received := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			received <- struct{}{}
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	errs := make(chan error)
+	go func() {
+		_, err := client.performRequest(context.Background(), http.MethodGet, ts.URL+"?block=true", nil, nil)
+		errs <- err
+	}()
+
+	<-received
+	client.CancelInflight()
+
+	assert.Equal(t, ErrClientClosed, <-errs)
+
+	_, err := client.List(context.Background())
+	assert.Equal(t, ErrClientClosed, err)
+
+	_, err = client.Fetch(context.Background(), uuid.New())
+	assert.Equal(t, ErrClientClosed, err)
+
+	_, err = client.Ping(context.Background())
+	assert.Equal(t, ErrClientClosed, err)
+
+	// a reset client is open again
+	_, err = client.Reset().List(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestClose(t *testing.T) {
+	accounts := newTestAccounts(4)
+
+	paging := newPagingServer(accounts)
+	defer paging.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/organisation/accounts" {
+			paging.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]OrganisationAccount{"data": accounts[0]})
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	// nobody consumes the iteration past its first account
+	results, err := client.ListIter(context.Background(), PageSizeListOption(2))
+	assert.NoError(t, err)
+	<-results
+
+	watched := make(chan struct{})
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- client.Watch(context.Background(), accounts[0].ID, time.Hour, func(OrganisationAccount) {
+			close(watched)
+		})
+	}()
+	<-watched
+
+	assert.NoError(t, client.Close())
+	assert.Equal(t, ErrClientClosed, <-watchErr)
+
+	for range results {
+	}
+
+	_, err = client.ListIter(context.Background())
+	assert.Equal(t, ErrClientClosed, err)
+}
+
+func TestCloseTimeout(t *testing.T) {
+	defer func(timeout time.Duration) {
+		closeTimeout = timeout
+	}(closeTimeout)
+	closeTimeout = 10 * time.Millisecond
+
+	client := NewClient("http://localhost")
+
+	// a goroutine that never exits
+	assert.True(t, client.lifecycle.start())
+
+	assert.Error(t, client.Close())
+}
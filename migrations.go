@@ -0,0 +1,71 @@
+package form3
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// migrations holds the registered migrations, keyed by the API versions they migrate between.
+	migrations   = map[migrationKey]MigrationFunc{}
+	migrationsMu sync.RWMutex
+)
+
+// MigrationFunc changes an organisation account shaped after one version of the
+// Form3 API into the shape of another one, e.g. by moving the value of a renamed
+// field. Since responses do not carry the version they were produced by, it must
+// detect whether the account needs migrating (e.g. because a field required by the
+// newer version is missing) and leave accounts already in the newer shape untouched.
+type MigrationFunc func(account *OrganisationAccount) error
+
+// migrationKey identifies a migration between two API versions.
+type migrationKey struct {
+	from string
+	to   string
+}
+
+// RegisterMigration registers fn as the migration of organisation accounts from the
+// API version from to the API version to, replacing any previously registered one.
+func RegisterMigration(from, to string, fn MigrationFunc) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	migrations[migrationKey{from: from, to: to}] = fn
+}
+
+// Migrate migrates the organisation account from the API version from to the API
+// version to, using the migration registered between them. It returns an error if
+// no such migration was registered.
+func Migrate(from, to string, account *OrganisationAccount) error {
+	if from == to {
+		return nil
+	}
+
+	fn, ok := lookupMigration(from, to)
+	if !ok {
+		return fmt.Errorf("no migration registered from API version %s to %s", from, to)
+	}
+
+	return fn(account)
+}
+
+// lookupMigration returns the migration registered between the two API versions.
+func lookupMigration(from, to string) (MigrationFunc, bool) {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	fn, ok := migrations[migrationKey{from: from, to: to}]
+
+	return fn, ok
+}
+
+// migrate passes an organisation account received from the API through the migration
+// from the default API version to the one of the client, if such a migration exists.
+func (c *Client) migrate(account *OrganisationAccount) error {
+	fn, ok := lookupMigration(defaultAPIVersion, c.apiVersion)
+	if !ok {
+		return nil
+	}
+
+	return fn(account)
+}
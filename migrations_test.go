@@ -0,0 +1,40 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrations(t *testing.T) {
+	// pretend v2 requires a type, which v1-era responses may not have
+	RegisterMigration("v1", "v2-test", func(account *OrganisationAccount) error {
+		if account.Type == "" {
+			account.Type = "accounts"
+		}
+		return nil
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2-test/organisation/accounts/"+uuid.Nil.String(), r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":{"version":1}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithAPIVersion("v2-test"))
+
+	org, err := client.Fetch(context.Background(), uuid.Nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "accounts", org.Type)
+
+	var account OrganisationAccount
+	assert.NoError(t, Migrate("v1", "v2-test", &account))
+	assert.Equal(t, "accounts", account.Type)
+	assert.NoError(t, Migrate("v1", "v1", &account))
+	assert.Error(t, Migrate("v1", "v3-test", &account))
+}
@@ -1,34 +1,281 @@
 package form3
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/google/uuid"
 )
 
 var (
-	// PageNumberListOption is a List call option to set the page number.
+	// PageNumberListOption is a List call option to set the page number. It is sent
+	// even if it is 0, unlike when the option is not given.
 	PageNumberListOption = func(pageNumber int) func(*listOptions) {
 		return func(lo *listOptions) {
+			if lo.pageToken != "" {
+				lo.err = fmt.Errorf("%w: paging by page number and page token", ErrConflictingListOptions)
+				return
+			}
 			lo.pageNumber = pageNumber
+			lo.pageNumberSet = true
+		}
+	}
+
+	// PageToken is a List call option to request the page identified by an opaque token,
+	// as returned in ListResult.NextPageToken by a previous ListPage call. The Form3 API
+	// may not support page tokens: the parameter is sent regardless. Combining it with
+	// PageNumberListOption makes List return ErrConflictingListOptions.
+	PageToken = func(token string) ListOption {
+		return func(lo *listOptions) {
+			if lo.pageNumberSet && token != "" {
+				lo.err = fmt.Errorf("%w: paging by page number and page token", ErrConflictingListOptions)
+				return
+			}
+			lo.pageToken = token
 		}
 	}
 
-	// PageSizeListOption is a List call option to set the page size.
+	// PageSizeListOption is a List call option to set the page size. It is sent even
+	// if it is 0, overriding the default page size of the client.
 	PageSizeListOption = func(pageSize int) func(*listOptions) {
 		return func(lo *listOptions) {
 			lo.pageSize = pageSize
+			lo.pageSizeSet = true
+		}
+	}
+
+	// FilterByIDs is a List call option to only return the accounts with the given IDs.
+	// If there are more IDs than the client accepts in a single request, List splits
+	// them into several requests and merges the results in the order of the IDs.
+	FilterByIDs = func(ids ...uuid.UUID) func(*listOptions) {
+		return func(lo *listOptions) {
+			lo.ids = append(lo.ids, ids...)
+		}
+	}
+
+	// FilterByOrganisationID is a List call option to only return the accounts belonging
+	// to the given organisation. Combining it with a filter on a different organisation
+	// makes List return ErrConflictingListOptions.
+	FilterByOrganisationID = func(id uuid.UUID) func(*listOptions) {
+		return func(lo *listOptions) {
+			if lo.organisationID != uuid.Nil && lo.organisationID != id {
+				lo.err = fmt.Errorf("%w: filtering by organisations %s and %s", ErrConflictingListOptions, lo.organisationID, id)
+				return
+			}
+			lo.organisationID = id
+		}
+	}
+
+	// FilterByAccountClassification is a List call option to only return the accounts
+	// with the given classification, e.g. ClassificationPersonal.
+	FilterByAccountClassification = func(classification AccountClassification) ListOption {
+		return func(lo *listOptions) {
+			lo.accountClassification = classification
+		}
+	}
+
+	// FilterByCreatedBy is a List call option to only return the accounts created by the
+	// given actor. The Form3 API may not support this filter: the parameter is sent
+	// regardless, and a server ignoring it returns every account.
+	FilterByCreatedBy = func(actor string) ListOption {
+		return func(lo *listOptions) {
+			lo.createdBy = actor
+		}
+	}
+
+	// IncludeDeleted is a List call option to also return the accounts that were
+	// soft-deleted. The Form3 API may not support soft-deletion: the parameter is sent
+	// regardless, and a server ignoring it only returns the accounts not deleted.
+	IncludeDeleted = func() ListOption {
+		return func(lo *listOptions) {
+			lo.includeDeleted = true
+		}
+	}
+
+	// OnlyDeleted is a List call option to only return the accounts that were
+	// soft-deleted. The Form3 API may not support soft-deletion: the parameter is sent
+	// regardless, and a server ignoring it returns the accounts not deleted instead.
+	OnlyDeleted = func() ListOption {
+		return func(lo *listOptions) {
+			lo.onlyDeleted = true
+		}
+	}
+
+	// SelectFields is a List call option to only return the given fields of the accounts,
+	// named after their JSON tags, e.g. "id" or "account_number", to reduce the size of
+	// the response. The fields left out are zero in the returned accounts. The Form3 API
+	// may not support sparse fieldsets: the parameter is sent regardless, and a server
+	// ignoring it returns every field. Naming an unknown field makes List return
+	// ErrUnknownField.
+	SelectFields = func(fields ...string) ListOption {
+		err := checkSelectableFields(fields)
+		return func(lo *listOptions) {
+			if err != nil {
+				lo.err = err
+				return
+			}
+			lo.fields = append(lo.fields, fields...)
+		}
+	}
+
+	// SparseFieldset is SelectFields under the name the JSON:API specification gives to
+	// the fields parameter, e.g. to only page through the IDs and account numbers of all
+	// accounts with ListAll.
+	SparseFieldset = SelectFields
+
+	// IncludeRelated is a List call option to ask the API to embed the resources related
+	// to the accounts through the given relationships, e.g. "organisation", in the response.
+	// They are returned in OrganisationAccount.Included. The Form3 API may not support
+	// this: the parameter is sent regardless, and a server ignoring it embeds nothing.
+	IncludeRelated = func(relationships ...string) ListOption {
+		return func(lo *listOptions) {
+			lo.include = append(lo.include, relationships...)
+		}
+	}
+
+	// FilterByModifiedAfter is a List call option to only return the accounts modified
+	// after the given time. The Form3 API may not support this filter: the parameter is
+	// sent regardless, and a server ignoring it returns every account.
+	FilterByModifiedAfter = func(t time.Time) ListOption {
+		return func(lo *listOptions) {
+			lo.modifiedAfter = t
+		}
+	}
+
+	// FilterByCreatedAfter is a List call option to only return the accounts created
+	// after the given time. The Form3 API may not support this filter: the parameter is
+	// sent regardless, and a server ignoring it returns every account.
+	FilterByCreatedAfter = func(t time.Time) ListOption {
+		return func(lo *listOptions) {
+			lo.createdAfter = t
 		}
 	}
 )
 
+var (
+	// IncludeFields is a Create and Update call option to only send the given attributes,
+	// named after their JSON tags, e.g. "name" or "bank_id". Naming an unknown attribute
+	// makes the call return ErrUnknownField.
+	IncludeFields = func(fields ...string) RequestOption {
+		err := checkAttributeFields(fields)
+		return func(ro *requestOptions) {
+			if err != nil {
+				ro.err = err
+				return
+			}
+			ro.includeFields = append(ro.includeFields, fields...)
+		}
+	}
+
+	// ExcludeFields is a Create and Update call option to send all attributes but the given
+	// ones, named after their JSON tags, e.g. "name" or "bank_id". Naming an unknown attribute
+	// makes the call return ErrUnknownField.
+	ExcludeFields = func(fields ...string) RequestOption {
+		err := checkAttributeFields(fields)
+		return func(ro *requestOptions) {
+			if err != nil {
+				ro.err = err
+				return
+			}
+			ro.excludeFields = append(ro.excludeFields, fields...)
+		}
+	}
+)
+
+var (
+	// IncludeRelatedFetch is a Fetch call option to ask the API to embed the resources
+	// related to the account through the given relationships, e.g. "organisation", in the
+	// response, just like IncludeRelated does for List.
+	IncludeRelatedFetch = func(relationships ...string) RequestOption {
+		return func(ro *requestOptions) {
+			ro.include = append(ro.include, relationships...)
+		}
+	}
+)
+
+var (
+	// WithPreferMinimal is a Create and Update call option to ask the API not to send
+	// the organisation account back, with a Prefer: return=minimal header. If the API
+	// honours it by responding with 204 No Content, the given account is returned as is.
+	WithPreferMinimal = func() RequestOption {
+		return func(ro *requestOptions) {
+			ro.prefer = append(ro.prefer, "return=minimal")
+		}
+	}
+
+	// WithPreferAsync is a Create and Update call option to ask the API to process the
+	// request asynchronously, with a Prefer: respond-async header. If the API honours it
+	// by responding with 202 Accepted and no body, the given account is returned as is.
+	WithPreferAsync = func() RequestOption {
+		return func(ro *requestOptions) {
+			ro.prefer = append(ro.prefer, "respond-async")
+		}
+	}
+)
+
+type requestOptions struct {
+	includeFields []string
+	excludeFields []string
+	prefer        []string
+	include       []string
+
+	// err is set by request options given invalid arguments
+	err error
+}
+
+// RequestOption is a function that can change what the Create, Update and Fetch
+// calls send to the Form3 API.
+type RequestOption = func(*requestOptions)
+
 type listOptions struct {
-	pageNumber int
-	pageSize   int
+	pageNumber     int
+	pageSize       int
+	pageToken      string
+	pageNumberSet  bool
+	pageSizeSet    bool
+	ids            []uuid.UUID
+	organisationID uuid.UUID
+	modifiedAfter  time.Time
+	createdAfter   time.Time
+
+	accountClassification AccountClassification
+	createdBy             string
+	includeDeleted        bool
+	onlyDeleted           bool
+	fields                []string
+	include               []string
+
+	// err is set by list options that conflict with each other
+	err error
+}
+
+// filtered returns whether the list options filter the organisation accounts,
+// as opposed to only paging through them.
+func (lo listOptions) filtered() bool {
+	return len(lo.ids) != 0 ||
+		lo.organisationID != uuid.Nil ||
+		lo.accountClassification != "" ||
+		lo.createdBy != "" ||
+		lo.onlyDeleted ||
+		!lo.modifiedAfter.IsZero() ||
+		!lo.createdAfter.IsZero()
+}
+
+// partial returns whether the list options make the API return accounts that differ
+// from fetched ones, either missing the fields left out of a sparse fieldset or
+// carrying related resources.
+func (lo listOptions) partial() bool {
+	return len(lo.fields) != 0 || len(lo.include) != 0
 }
 
 // ListOption is a function that can determine whether the List call
 // to the Form3 API should have any paging settings.
 type ListOption = func(*listOptions)
 
+// AccountType is the type of every organisation account in the Form3 API.
+const AccountType = "accounts"
+
 // OrganisationAccount represents a bank account that is registered with Form3.
 // It is used to validate and allocate inbound payments.
 type OrganisationAccount struct {
@@ -37,23 +284,74 @@ type OrganisationAccount struct {
 	OrganisationID uuid.UUID                     `json:"organisation_id"`
 	Version        int                           `json:"version"`
 	Attributes     OrganisationAccountAttributes `json:"attributes"`
+
+	// CreatedBy and ModifiedBy identify the actors who created and last modified the
+	// account. They are only set if the API returns them.
+	CreatedBy  string `json:"created_by,omitempty"`
+	ModifiedBy string `json:"modified_by,omitempty"`
+
+	// CreatedOn and DeletedOn are when the account was created and, if it was
+	// soft-deleted, deleted. They are only set if the API returns them.
+	CreatedOn *time.Time `json:"created_on,omitempty"`
+	DeletedOn *time.Time `json:"deleted_on,omitempty"`
+
+	// Relationships links the account to other resources, keyed by the name of the
+	// relationship. See Client.FetchRelated.
+	Relationships map[string]RelationshipData `json:"relationships,omitempty"`
+
+	// Included holds the related resources embedded in the response on request, e.g.
+	// with IncludeRelated, keyed by the name of the relationship. Each value is the JSON
+	// array of the resources related through it. It is never sent to the API.
+	Included map[string]json.RawMessage `json:"included,omitempty"`
+}
+
+// RelationshipData holds the resources an organisation account is related to
+// through a given relationship.
+type RelationshipData struct {
+	Data []RelationshipRef `json:"data"`
+}
+
+// RelationshipRef identifies a resource an organisation account is related to.
+type RelationshipRef struct {
+	ID   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+}
+
+// DefaultType returns an empty organisation account whose type is already set to
+// AccountType, ready for the rest of its fields to be filled in.
+func DefaultType() OrganisationAccount {
+	return OrganisationAccount{Type: AccountType}
 }
 
 // OrganisationAccountAttributes represent various attributes that can be included
 // inside the organisation account entity.
 type OrganisationAccountAttributes struct {
-	Country                 string   `json:"country"`
-	BaseCurrency            string   `json:"base_currency"`
-	AccountNumber           string   `json:"account_number"`
-	BankID                  string   `json:"bank_id"`
-	BankIDCode              string   `json:"bank_id_code"`
-	BIC                     string   `json:"bic"`
-	IBAN                    string   `json:"iban"`
-	Name                    []string `json:"name"`
-	AlternativeNames        []string `json:"alternative_names"`
-	AccountClassification   string   `json:"account_classification"`
-	JointAccount            bool     `json:"joint_account"`
-	AccountMatchingOptOut   bool     `json:"account_matching_opt_out"`
-	SecondaryIdentification string   `json:"secondary_identification"`
-	Switched                bool     `json:"switched"`
+	Country                 Country               `json:"country"`
+	BaseCurrency            string                `json:"base_currency"`
+	AccountNumber           *string               `json:"account_number,omitempty"`
+	BankID                  string                `json:"bank_id"`
+	BankIDCode              BankIDCode            `json:"bank_id_code"`
+	BIC                     *string               `json:"bic,omitempty"`
+	IBAN                    *string               `json:"iban,omitempty"`
+	Name                    []string              `json:"name"`
+	AlternativeNames        []string              `json:"alternative_names"`
+	AccountClassification   AccountClassification `json:"account_classification"`
+	JointAccount            *bool                 `json:"joint_account,omitempty"`
+	AccountMatchingOptOut   bool                  `json:"account_matching_opt_out"`
+	SecondaryIdentification *string               `json:"secondary_identification,omitempty"`
+	Switched                *bool                 `json:"switched,omitempty"`
+}
+
+// String returns a pointer to the given string. It is a convenience for setting
+// the optional string fields of OrganisationAccountAttributes, which are nil
+// when they are not provided.
+func String(s string) *string {
+	return &s
+}
+
+// Bool returns a pointer to the given bool. It is a convenience for setting
+// the optional bool fields of OrganisationAccountAttributes, which are nil
+// when they are not provided.
+func Bool(b bool) *bool {
+	return &b
 }
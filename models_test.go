@@ -0,0 +1,129 @@
+package form3
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganisationAccountAttributesJSON(t *testing.T) {
+	testCases := []struct {
+		name         string
+		attributes   OrganisationAccountAttributes
+		expectedKeys []string
+		missingKeys  []string
+	}{
+		{
+			name:        "OK - unset optional fields are omitted",
+			attributes:  OrganisationAccountAttributes{Country: "GB"},
+			missingKeys: []string{"account_number", "bic", "iban", "secondary_identification", "joint_account", "switched"},
+		},
+		{
+			name: "OK - empty but set optional fields are kept",
+			attributes: OrganisationAccountAttributes{
+				Country:                 "GB",
+				AccountNumber:           String("41426819"),
+				BIC:                     String(""),
+				IBAN:                    String("GB11NWBK40030041426819"),
+				SecondaryIdentification: String(""),
+				JointAccount:            Bool(false),
+				Switched:                Bool(false),
+			},
+			expectedKeys: []string{"account_number", "bic", "iban", "secondary_identification", "joint_account", "switched"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.attributes)
+			assert.NoError(t, err)
+
+			var fields map[string]interface{}
+			assert.NoError(t, json.Unmarshal(b, &fields))
+
+			for _, key := range tc.expectedKeys {
+				assert.Contains(t, fields, key)
+			}
+			for _, key := range tc.missingKeys {
+				assert.NotContains(t, fields, key)
+			}
+
+			var decoded OrganisationAccountAttributes
+			assert.NoError(t, json.Unmarshal(b, &decoded))
+			assert.Equal(t, tc.attributes, decoded)
+		})
+	}
+}
+
+func FuzzOrganisationAccountJSON(f *testing.F) {
+	for _, path := range []string{"testdata/organisations.json", "testdata/create_organisations.json"} {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		var organisations []json.RawMessage
+		if err := json.Unmarshal(b, &organisations); err != nil {
+			f.Fatal(err)
+		}
+
+		for _, org := range organisations {
+			f.Add([]byte(org))
+		}
+	}
+
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`{"id":null,"attributes":null}`))
+	f.Add([]byte(`{"attributes":{"name":[],"alternative_names":null,"iban":null,"switched":null}}`))
+	f.Add([]byte(`{"attributes":{"name":["` + strings.Repeat("a", 10000) + `"]}}`))
+	f.Add([]byte(`{"id":"a9e3b971-a241-4930-a09f`))
+	f.Add([]byte(`{"included":{"a":[1, 2]}}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var account OrganisationAccount
+		if err := json.Unmarshal(data, &account); err != nil {
+			return
+		}
+
+		b, err := json.Marshal(account)
+		if err != nil {
+			t.Fatalf("could not marshal decoded account: %v", err)
+		}
+
+		var roundTripped OrganisationAccount
+		if err := json.Unmarshal(b, &roundTripped); err != nil {
+			t.Fatalf("could not unmarshal marshalled account: %v", err)
+		}
+
+		// marshalling compacts the raw JSON of the included resources, thus they are
+		// compared once compacted, apart from the rest of the account
+		assert.Equal(t, compactIncluded(t, account.Included), compactIncluded(t, roundTripped.Included))
+		account.Included, roundTripped.Included = nil, nil
+
+		assert.Equal(t, account, roundTripped)
+	})
+}
+
+// compactIncluded returns the included resources with their raw JSON compacted.
+func compactIncluded(t *testing.T, included map[string]json.RawMessage) map[string]string {
+	if included == nil {
+		return nil
+	}
+
+	compacted := make(map[string]string, len(included))
+	for name, raw := range included {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			t.Fatalf("could not compact included resources %q: %v", name, err)
+		}
+		compacted[name] = buf.String()
+	}
+
+	return compacted
+}
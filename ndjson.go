@@ -0,0 +1,42 @@
+package form3
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteNDJSON writes the accounts to w as newline-delimited JSON, i.e. one JSON
+// object per line.
+func WriteNDJSON(w io.Writer, accounts []OrganisationAccount) error {
+	encoder := json.NewEncoder(w)
+
+	for _, account := range accounts {
+		err := encoder.Encode(account)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteNDJSONIter writes the accounts received from iter, e.g. as returned by ListIter,
+// to w as newline-delimited JSON, each as soon as it is received. It returns once iter
+// is closed, or as soon as a write fails or iter sends an error, returning that error;
+// the caller should then cancel the context of the iteration to release its goroutine.
+func WriteNDJSONIter(w io.Writer, iter <-chan OrganisationAccountResult) error {
+	encoder := json.NewEncoder(w)
+
+	for result := range iter {
+		if result.Err != nil {
+			return result.Err
+		}
+
+		err := encoder.Encode(result.Account)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package form3
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter accepts the given number of writes, then fails.
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.writes--
+	return len(p), nil
+}
+
+// readNDJSON decodes the accounts written as newline-delimited JSON, one per line.
+func readNDJSON(t *testing.T, b []byte) []OrganisationAccount {
+	var accounts []OrganisationAccount
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var account OrganisationAccount
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &account))
+		accounts = append(accounts, account)
+	}
+
+	return accounts
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	accounts := newTestAccounts(3)
+
+	var buf bytes.Buffer
+	err := WriteNDJSON(&buf, accounts)
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, readNDJSON(t, buf.Bytes()))
+
+	err = WriteNDJSON(&failingWriter{writes: 1}, accounts)
+	assert.EqualError(t, err, "disk full")
+}
+
+func TestWriteNDJSONIter(t *testing.T) {
+	accounts := newTestAccounts(5)
+
+	ts := newPagingServer(accounts)
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	iter, err := client.ListIter(ctx, PageSizeListOption(2))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = WriteNDJSONIter(&buf, iter)
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, readNDJSON(t, buf.Bytes()))
+
+	results := make(chan OrganisationAccountResult, 2)
+	results <- OrganisationAccountResult{Account: accounts[0]}
+	results <- OrganisationAccountResult{Err: ErrNotFound}
+	close(results)
+
+	buf.Reset()
+	err = WriteNDJSONIter(&buf, results)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, accounts[:1], readNDJSON(t, buf.Bytes()))
+}
@@ -0,0 +1,551 @@
+package form3
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff"
+)
+
+var (
+	// WithBackoffStrategy is a client option to replace the exponential back-off
+	// used when retrying requests. fn is called once per logical request, thus
+	// every request starts with a fresh back-off instance.
+	WithBackoffStrategy = func(fn func() backoff.BackOff) ClientOption {
+		return func(c *Client) {
+			c.newBackOff = fn
+			c.customBackOff = true
+		}
+	}
+
+	// WithBackoffInitialInterval is a client option to set how long the default
+	// exponential back-off waits before the first retry (500ms if not set).
+	WithBackoffInitialInterval = func(d time.Duration) ClientOption {
+		return func(c *Client) {
+			c.backoffInitialInterval = d
+		}
+	}
+
+	// WithBackoffMultiplier is a client option to set by how much the default
+	// exponential back-off grows the wait after every retry (1.5 if not set).
+	WithBackoffMultiplier = func(f float64) ClientOption {
+		return func(c *Client) {
+			c.backoffMultiplier = f
+		}
+	}
+
+	// WithBackoffMaxInterval is a client option to cap how long the default exponential
+	// back-off waits between two attempts (60s if not set). This is unrelated to the total
+	// time spent retrying a request, which is capped separately.
+	WithBackoffMaxInterval = func(d time.Duration) ClientOption {
+		return func(c *Client) {
+			c.backoffMaxInterval = d
+		}
+	}
+
+	// WithTimeout is a client option to change how long a single attempt of a request
+	// may take, including reading the response body (10s if not set).
+	WithTimeout = func(d time.Duration) ClientOption {
+		return func(c *Client) {
+			c.httpClient.client.Timeout = d
+		}
+	}
+
+	// WithAPIKey is a client option to authenticate every request with the given
+	// API key, sent as a bearer token in the Authorization header.
+	WithAPIKey = func(key string) ClientOption {
+		return func(c *Client) {
+			c.apiKey = key
+		}
+	}
+
+	// WithMaxRetries is a client option to cap the number of times a request is retried,
+	// on top of the total time spent retrying it. 0 disables retries altogether.
+	WithMaxRetries = func(n int) ClientOption {
+		return func(c *Client) {
+			c.maxRetries = n
+		}
+	}
+
+	// WithMaxBufferedBodySize is a client option to change how many bytes of a request
+	// body the client is willing to buffer in memory to be able to retry the request
+	// (10 MB if not set). Larger bodies that cannot be rewound make the request fail
+	// with ErrBodyTooLargeToBuffer.
+	WithMaxBufferedBodySize = func(n int64) ClientOption {
+		return func(c *Client) {
+			c.maxBodyBuffer = n
+		}
+	}
+
+	// WithPingEndpoint is a client option to change the path, relative to the base URL,
+	// of the endpoint that Ping sends its requests to (e.g. "/v1/health" if not set).
+	WithPingEndpoint = func(path string) ClientOption {
+		return func(c *Client) {
+			c.pingEndpoint = path
+		}
+	}
+
+	// WithTraceHook is a client option to trace every attempt at performing a request,
+	// e.g. with a tracing library of choice, without the client depending on any.
+	WithTraceHook = func(h TraceHook) ClientOption {
+		return func(c *Client) {
+			c.traceHook = h
+		}
+	}
+
+	// WithStaleOnError is a client option to make Fetch and List fall back to the last
+	// result they got for the same request, as long as it is not older than ttl, when
+	// the API cannot be reached or keeps failing with a 5xx or 429 status. The stale
+	// result is returned along with an error wrapping ErrStaleData. Writes are never
+	// served from this fallback, and a successful Update or Delete drops the results
+	// kept for the fetched account.
+	WithStaleOnError = func(ttl time.Duration) ClientOption {
+		return func(c *Client) {
+			c.stale = newStaleCache(ttl)
+		}
+	}
+
+	// WithResponseObserver is a client option to observe every response received from
+	// the API, including error responses and the ones of retried attempts, e.g. to archive
+	// them for audit. fn is called before the response is decoded, with a copy of at most
+	// the first MaxResponseBodyBytes of its body (see WithMaxResponseBodyBytes).
+	WithResponseObserver = func(fn func(method, url string, statusCode int, body []byte)) ClientOption {
+		return func(c *Client) {
+			c.responseObserver = fn
+		}
+	}
+
+	// WithMaxResponseBodyBytes is a client option to change how many bytes of a response
+	// body are copied for the response observer (10 MB if not set).
+	WithMaxResponseBodyBytes = func(n int64) ClientOption {
+		return func(c *Client) {
+			c.maxResponseBodyBytes = n
+		}
+	}
+
+	// WithVersionCheck is a client option to make the client call CheckVersion before
+	// its first request, which fails with ErrUnsupportedAPIVersion if the API runs an
+	// incompatible version, as do all the following ones.
+	WithVersionCheck = func() ClientOption {
+		return func(c *Client) {
+			c.versionCheck = &versionCheck{}
+		}
+	}
+
+	// WithFieldEncryption is a client option to encrypt the given attributes, named after
+	// their JSON tags, e.g. "account_number" or "iban", before sending them to the API, and
+	// to decrypt them once received. Values are encrypted with AES-GCM using key, which must
+	// be 16, 24 or 32 bytes long, and sent base64-encoded. Only string attributes can be
+	// encrypted. Since NewClient cannot fail, invalid arguments make every request fail.
+	WithFieldEncryption = func(key []byte, fields ...string) ClientOption {
+		return func(c *Client) {
+			encryptor, err := newFieldEncryptor(key, fields)
+			if err != nil {
+				c.configErr = err
+				return
+			}
+			c.encryptor = encryptor
+		}
+	}
+
+	// WithFieldEncryptionKey is a client option to rotate the key used by WithFieldEncryption,
+	// which it must follow. Values are then encrypted with the new key, while the previous
+	// keys are still tried when decrypting, so that values encrypted before the rotation
+	// can still be read.
+	WithFieldEncryptionKey = func(key []byte) ClientOption {
+		return func(c *Client) {
+			if c.encryptor == nil {
+				c.configErr = errors.New("WithFieldEncryptionKey must follow WithFieldEncryption")
+				return
+			}
+
+			aead, err := newAEAD(key)
+			if err != nil {
+				c.configErr = err
+				return
+			}
+			c.encryptor.aeads = append([]cipher.AEAD{aead}, c.encryptor.aeads...)
+		}
+	}
+
+	// WithBackoffJitter is a client option to set how much the default exponential back-off
+	// randomises each wait, so that clients failing at the same time do not retry at the same
+	// time either. factor ranges from 0 (no jitter) to 1 (waits anywhere between 0 and twice
+	// the interval), e.g. 0.5 waits the interval ±50%, which is the default. Values out of
+	// that range are clamped to it.
+	WithBackoffJitter = func(factor float64) ClientOption {
+		return func(c *Client) {
+			c.backoffJitter = math.Max(0, math.Min(1, factor))
+		}
+	}
+
+	// WithCorrelationIDExtractor is a client option to propagate the correlation ID of the
+	// work being done, e.g. the incoming request being served, to the API. fn is called with
+	// the context of every request, and any ID it returns is sent in the X-Correlation-ID
+	// header. See HTTPCorrelationIDExtractor.
+	WithCorrelationIDExtractor = func(fn func(ctx context.Context) string) ClientOption {
+		return func(c *Client) {
+			c.correlationID = fn
+		}
+	}
+
+	// WithContentType is a client option to set the media type of the bodies exchanged
+	// with the API, sent in the Accept header and, for requests with a body, in the
+	// Content-Type header, e.g. "application/msgpack". Request bodies are still encoded as
+	// JSON, and responses are decoded by the decoder set with WithResponseDecoder.
+	WithContentType = func(contentType string) ClientOption {
+		return func(c *Client) {
+			c.contentType = contentType
+			c.accept = contentType
+		}
+	}
+
+	// WithAcceptHeader is a client option to set the media type sent in the Accept
+	// header, to negotiate the format of the responses with the API, e.g.
+	// "application/json". It defaults to "application/vnd.api+json", or to the media
+	// type set by WithContentType. An invalid media type makes every request return
+	// an error.
+	WithAcceptHeader = func(mediaType string) ClientOption {
+		return func(c *Client) {
+			_, _, err := mime.ParseMediaType(mediaType)
+			if err != nil {
+				c.configErr = fmt.Errorf("invalid accept header %q: %w", mediaType, err)
+				return
+			}
+			c.accept = mediaType
+		}
+	}
+
+	// WithResponseDecoder is a client option to decode the responses of the API with d
+	// instead of JSONDecoder, e.g. to read MessagePack. It is usually given along with
+	// WithContentType(d.ContentType()).
+	WithResponseDecoder = func(d ResponseDecoder) ClientOption {
+		return func(c *Client) {
+			c.decoder = d
+		}
+	}
+
+	// WithFieldAliases is a client option to decode the organisation accounts received
+	// from the API with the given aliases of deprecated fields. Aliases are only applied
+	// by the client they are given to, and later changes to aliases do not affect it.
+	WithFieldAliases = func(aliases FieldAliases) ClientOption {
+		return func(c *Client) {
+			if c.fieldAliases == nil {
+				c.fieldAliases = FieldAliases{}
+			}
+			for old, new := range aliases {
+				c.fieldAliases.RegisterFieldAlias(old, new)
+			}
+		}
+	}
+
+	// WithStrictDecoding is a client option to fail decoding the organisation accounts
+	// received from the API if they hold fields the client does not know about, e.g. to
+	// detect changes of the API early. Fields missing from responses, such as those left
+	// out with SelectFields, are still allowed and left to their zero value.
+	WithStrictDecoding = func() ClientOption {
+		return func(c *Client) {
+			c.strictDecoding = true
+		}
+	}
+
+	// WithListGuard is a client option to make List calls without any filter fail with
+	// ErrListGuardExceeded if they return more than maxResults accounts, as listing every
+	// account is expensive and often a sign that a filter was forgotten. Paging options
+	// are not filters, thus pages larger than maxResults trip the guard.
+	WithListGuard = func(maxResults int) ClientOption {
+		return func(c *Client) {
+			c.listGuard = maxResults
+		}
+	}
+
+	// WithMaxListResults is a client option to make ListAll, ListParallel, ListIter and
+	// Walk fail with ErrMaxResultsExceeded once they collected more than n accounts, to
+	// protect callers from paging through a dataset that grew larger than expected.
+	WithMaxListResults = func(n int) ClientOption {
+		return func(c *Client) {
+			c.maxListResults = n
+		}
+	}
+
+	// WithRetryBudget is a client option to spend the retries of the client from the
+	// given budget, which may be shared with other clients. When a request would be
+	// retried but the budget is exhausted, the request fails straight away with a
+	// *RetryError, as if the back-off strategy had given up. Only the retries granted
+	// by the back-off strategy are charged to the budget.
+	WithRetryBudget = func(b *RetryBudget) ClientOption {
+		return func(c *Client) {
+			c.retryBudget = b
+		}
+	}
+
+	// WithTenantHeader is a client option to send the tenant ID stored in the context
+	// by TenantContext in the given header, e.g. "X-Org-Scope", instead of X-Tenant-ID.
+	WithTenantHeader = func(headerName string) ClientOption {
+		return func(c *Client) {
+			c.tenantHeader = headerName
+		}
+	}
+
+	// WithAuditLogger is a client option to log an AuditEvent to al after every Create,
+	// Fetch, Update and Delete call, whether it succeeded or not.
+	WithAuditLogger = func(al AuditLogger) ClientOption {
+		return func(c *Client) {
+			c.auditLogger = al
+		}
+	}
+
+	// WithActorExtractor is a client option to identify who performs the operations
+	// recorded by the audit logger. fn is called with the context of every audited call,
+	// and returns the ID of the actor, e.g. a user ID set by an authentication middleware.
+	WithActorExtractor = func(fn func(ctx context.Context) string) ClientOption {
+		return func(c *Client) {
+			c.actorExtractor = fn
+		}
+	}
+
+	// WithScheduledRateLimit is a client option to throttle requests depending on the
+	// time of day, e.g. because the API applies stricter rate limits during business
+	// hours. rateByHour maps UTC hours, from 0 to 23, to the number of requests per
+	// second allowed during them; requests made during other hours are not throttled.
+	// Each request waits for the rate limit of the hour it starts in. An invalid hour
+	// or a rate that is not positive makes every request return an error.
+	WithScheduledRateLimit = func(rateByHour map[int]float64) ClientOption {
+		return func(c *Client) {
+			schedule, err := newRateSchedule(rateByHour)
+			if err != nil {
+				c.configErr = err
+				return
+			}
+			c.rateSchedule = schedule
+		}
+	}
+
+	// WithConflictMergeStrategy is a client option to resolve the conflicts met when
+	// creating organisation accounts that already exist. When the API responds to
+	// Create with 409 Conflict, the existing account is fetched and updated with the
+	// account returned by mergeFn, which is given the existing account and the one
+	// that was to be created; the ID and version of the existing account are kept.
+	// The merge is attempted once, so a conflict while updating is returned as is. If
+	// the existing account cannot be fetched, e.g. because the conflict is on another
+	// unique attribute than the ID, the conflict is returned, mentioning why. With
+	// WithAuditLogger, the create is audited as failed, and the fetch and the update
+	// are audited on their own.
+	WithConflictMergeStrategy = func(mergeFn func(existing, incoming OrganisationAccount) OrganisationAccount) ClientOption {
+		return func(c *Client) {
+			c.conflictMerge = mergeFn
+		}
+	}
+
+	// WithRetryOnNetworkError is a client option to also retry the requests failing
+	// because the connection was closed before the response was read (EOF) or was reset
+	// by the peer, which are usually transient. Any other network error is returned
+	// straight away. The retries are observed just like the ones for a retriable status.
+	WithRetryOnNetworkError = func() ClientOption {
+		return func(c *Client) {
+			c.retryOnNetworkError = true
+		}
+	}
+
+	// WithServiceMeshRetryHeaders is a client option to send the given headers with every
+	// request, e.g. "x-envoy-retry-on", to let a service mesh know which requests it may
+	// retry. Headers set by the client itself for a request, such as If-None-Match or
+	// Prefer, take precedence. Usually given along with WithDisableClientRetry.
+	WithServiceMeshRetryHeaders = func(headers map[string]string) ClientOption {
+		return func(c *Client) {
+			c.meshHeaders = make(http.Header, len(headers))
+			for key, value := range headers {
+				c.meshHeaders.Set(key, value)
+			}
+		}
+	}
+
+	// WithDisableClientRetry is a client option to send every request only once, leaving
+	// all retries to a service mesh so that failed requests are not retried by both. It is
+	// the same as WithMaxRetries(0): note that setting the MaxElapsedTime of the back-off
+	// to 0 would instead make the client retry forever.
+	WithDisableClientRetry = func() ClientOption {
+		return func(c *Client) {
+			c.maxRetries = 0
+		}
+	}
+
+	// WithLogger is a client option to make the client log what it is doing, e.g.
+	// the outcome of WarmUp. A *log.Logger can be used as is.
+	WithLogger = func(l Logger) ClientOption {
+		return func(c *Client) {
+			c.logger = l
+		}
+	}
+
+	// WithOnRetry is a client option to observe retries, e.g. for metrics or alerting.
+	// fn is called synchronously after each failed attempt that is retried, before waiting
+	// for the next one, with the 1-based attempt number, the response (if any) and the
+	// error (if any). The last attempt of a request that gives up is not reported.
+	// The response body is owned by the client and must not be read or closed by fn.
+	WithOnRetry = func(fn func(attempt int, resp *http.Response, err error)) ClientOption {
+		return func(c *Client) {
+			c.onRetry = fn
+		}
+	}
+
+	// WithMaxFilterIDs is a client option to set how many IDs passed to FilterByIDs
+	// are sent in a single List request. The default is 100.
+	WithMaxFilterIDs = func(max int) ClientOption {
+		return func(c *Client) {
+			c.maxFilterIDs = max
+		}
+	}
+
+	// WithDefaultPageSize is a client option to send page[size]=n with every List call
+	// that does not set a page size through PageSizeListOption, instead of relying on
+	// the default page size of the API.
+	WithDefaultPageSize = func(n int) ClientOption {
+		return func(c *Client) {
+			c.defaultPageSize = n
+		}
+	}
+
+	// WithPaginationDelay is a client option to wait d between fetching successive
+	// pages in ListAll, ListIter and Walk, to avoid hitting the rate limit of the API.
+	WithPaginationDelay = func(d time.Duration) ClientOption {
+		return func(c *Client) {
+			c.paginationDelay = d
+		}
+	}
+
+	// WithPreCreateHook is a client option to transform organisation accounts before
+	// they are sent by Create, e.g. to normalise them. It can be passed several times,
+	// in which case the hooks are called in order; if one of them returns an error,
+	// Create returns that error without making a request.
+	WithPreCreateHook = func(fn func(*OrganisationAccount) error) ClientOption {
+		return func(c *Client) {
+			c.preCreateHooks = append(c.preCreateHooks, fn)
+		}
+	}
+
+	// WithPostFetchHook is a client option to transform organisation accounts after
+	// they are received by Fetch or List. It can be passed several times, in which case
+	// the hooks are called in order; if one of them returns an error, that error is returned.
+	WithPostFetchHook = func(fn func(*OrganisationAccount) error) ClientOption {
+		return func(c *Client) {
+			c.postFetchHooks = append(c.postFetchHooks, fn)
+		}
+	}
+
+	// WithEnvelope is a client option to adapt the client to an API that wraps resources
+	// under different keys than "data". Empty keys keep their default value.
+	WithEnvelope = func(cfg EnvelopeConfig) ClientOption {
+		return func(c *Client) {
+			if cfg.DataKey != "" {
+				c.envelope.DataKey = cfg.DataKey
+			}
+			if cfg.ListDataKey != "" {
+				c.envelope.ListDataKey = cfg.ListDataKey
+			}
+		}
+	}
+
+	// WithAPIVersion is a client option to talk to another version of the Form3 API
+	// than v1, e.g. "v2". Accounts received from the API are passed through the migration
+	// registered from v1 to that version, if any (see RegisterMigration).
+	WithAPIVersion = func(version string) ClientOption {
+		return func(c *Client) {
+			c.apiVersion = version
+		}
+	}
+
+	// WithHTTPTransport is a client option to replace the transport used to perform
+	// requests. It takes priority over all options that configure the default transport,
+	// such as WithHTTP2.
+	WithHTTPTransport = func(rt http.RoundTripper) ClientOption {
+		return func(c *Client) {
+			c.roundTripper = rt
+		}
+	}
+
+	// WithHTTP2 is a client option to make the default transport attempt HTTP/2,
+	// which allows multiplexing requests over a single connection. HTTP/2 is only
+	// negotiated over TLS, thus this option is a no-op for plain HTTP base URLs.
+	WithHTTP2 = func() ClientOption {
+		return func(c *Client) {
+			c.transport.ForceAttemptHTTP2 = true
+
+			if c.transport.TLSClientConfig == nil {
+				c.transport.TLSClientConfig = &tls.Config{}
+			}
+			c.transport.TLSClientConfig.NextProtos = []string{"h2", "http/1.1"}
+		}
+	}
+
+	// WithDialer is a client option to make the default transport open connections
+	// using the given dialer, e.g. one with a custom Resolver for environments where
+	// DNS resolution is controlled.
+	WithDialer = func(d *net.Dialer) ClientOption {
+		return func(c *Client) {
+			c.dialer = d
+			c.transport.DialContext = d.DialContext
+		}
+	}
+
+	// WithDNSTimeout is a client option to limit how long the default transport waits
+	// for a connection to be established, DNS resolution included.
+	WithDNSTimeout = func(timeout time.Duration) ClientOption {
+		return WithDialer(&net.Dialer{Timeout: timeout})
+	}
+
+	// WithTCPKeepAlive is a client option to make the default transport send TCP
+	// keep-alive probes on idle connections every interval, so that connections closed
+	// by the server are detected before they are reused. It applies on top of the dialer
+	// set by WithDialer or WithDNSTimeout if given first, without changing it.
+	WithTCPKeepAlive = func(interval time.Duration) ClientOption {
+		return func(c *Client) {
+			// the same settings as the dialer of http.DefaultTransport
+			dialer := net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+			if c.dialer != nil {
+				dialer = *c.dialer
+			}
+			dialer.KeepAlive = interval
+
+			WithDialer(&dialer)(c)
+		}
+	}
+
+	// WithDisableKeepAlives is a client option to make the default transport open a
+	// new connection for every request, e.g. in environments such as AWS Lambda where
+	// idle connections are unlikely to survive until the next request.
+	WithDisableKeepAlives = func() ClientOption {
+		return func(c *Client) {
+			c.transport.DisableKeepAlives = true
+		}
+	}
+)
+
+// ClientOption is a function that can change the default configuration
+// of the client when passed to NewClient.
+type ClientOption = func(*Client)
+
+// Logger is the interface used by the client to log messages.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// TraceHook is the interface used by the client to trace the requests it performs.
+// Both methods are called for every attempt, thus several times for retried requests.
+type TraceHook interface {
+	// BeforeRequest is called right before the request is sent. It may change the
+	// request headers, e.g. to propagate a trace ID, and returns the context that is
+	// passed to AfterRequest, e.g. holding a span started from the request context.
+	BeforeRequest(req *http.Request) context.Context
+	// AfterRequest is called once the response headers are received or the request
+	// has failed, with how long that took. The response body must not be read.
+	AfterRequest(ctx context.Context, resp *http.Response, err error, elapsed time.Duration)
+}
@@ -0,0 +1,222 @@
+package form3
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithHTTP2(t *testing.T) {
+	t.Run("OK - default transport attempts HTTP/2", func(t *testing.T) {
+		client := NewClient("https://localhost", WithHTTP2())
+
+		transport, ok := client.httpClient.load().Transport.(*http.Transport)
+		assert.True(t, ok)
+		assert.True(t, transport.ForceAttemptHTTP2)
+		assert.Equal(t, []string{"h2", "http/1.1"}, transport.TLSClientConfig.NextProtos)
+	})
+
+	t.Run("OK - custom transport takes priority", func(t *testing.T) {
+		rt := roundTripperFunc(http.DefaultTransport.RoundTrip)
+
+		client := NewClient("https://localhost", WithHTTPTransport(rt), WithHTTP2())
+
+		_, ok := client.httpClient.load().Transport.(roundTripperFunc)
+		assert.True(t, ok)
+	})
+}
+
+func TestWithDialer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	var dialed bool
+	dialer := &net.Dialer{
+		Control: func(network, address string, conn syscall.RawConn) error {
+			dialed = true
+			return nil
+		},
+	}
+
+	client := NewClient(ts.URL, WithDialer(dialer))
+
+	_, err := client.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, dialed)
+}
+
+func TestWithTCPKeepAlive(t *testing.T) {
+	client := NewClient("http://localhost", WithTCPKeepAlive(10*time.Second))
+	assert.Equal(t, 10*time.Second, client.dialer.KeepAlive)
+	assert.Equal(t, 30*time.Second, client.dialer.Timeout)
+
+	dialer := &net.Dialer{Timeout: time.Second}
+	client = NewClient("http://localhost", WithDialer(dialer), WithTCPKeepAlive(time.Minute))
+	assert.Equal(t, time.Minute, client.dialer.KeepAlive)
+	assert.Equal(t, time.Second, client.dialer.Timeout)
+	assert.Equal(t, time.Duration(0), dialer.KeepAlive, "the given dialer is left unchanged")
+}
+
+func TestWithDisableKeepAlives(t *testing.T) {
+	var closing []bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		closing = append(closing, r.Close)
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	for _, client := range []*Client{NewClient(ts.URL), NewClient(ts.URL, WithDisableKeepAlives())} {
+		_, err := client.List(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []bool{false, true}, closing)
+}
+
+func TestSetTransport(t *testing.T) {
+	var calls int
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	_, err := client.List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+
+	client.SetTransport(rt)
+
+	_, err = client.List(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, timeout, client.httpClient.load().Timeout)
+}
+
+func TestSetTransportWhileRequestsAreInFlight(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := client.Fetch(context.Background(), uuid.New())
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			client.SetTransport(http.DefaultTransport)
+			client.SetHTTPClient(http.Client{Timeout: timeout})
+		}()
+	}
+	wg.Wait()
+}
+
+// traceRecorder is a TraceHook recording the requests it sees.
+type traceRecorder struct {
+	before []string
+	after  []int
+}
+
+type traceKey struct{}
+
+func (r *traceRecorder) BeforeRequest(req *http.Request) context.Context {
+	r.before = append(r.before, req.Method+" "+req.URL.Path)
+	req.Header.Set("X-Trace-Id", "trace")
+	return context.WithValue(req.Context(), traceKey{}, len(r.before))
+}
+
+func (r *traceRecorder) AfterRequest(ctx context.Context, resp *http.Response, err error, elapsed time.Duration) {
+	if err == nil {
+		r.after = append(r.after, resp.StatusCode)
+	}
+	_ = ctx.Value(traceKey{}).(int)
+}
+
+func TestWithTraceHook(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "trace", r.Header.Get("X-Trace-Id"))
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	hook := &traceRecorder{}
+	client := NewClient(ts.URL, WithTraceHook(hook), WithBackoffStrategy(ConstantBackoff(time.Millisecond)))
+
+	_, err := client.Fetch(context.Background(), uuid.Nil)
+
+	assert.NoError(t, err)
+	path := "GET /v1/organisation/accounts/" + uuid.Nil.String()
+	assert.Equal(t, []string{path, path}, hook.before)
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK}, hook.after)
+}
+
+func TestWithResponseObserver(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"error_message":"try again"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"type":"accounts"}}`))
+	}))
+	defer ts.Close()
+
+	var statusCodes []int
+	var bodies []string
+	client := NewClient(
+		ts.URL,
+		WithBackoffStrategy(ConstantBackoff(time.Millisecond)),
+		WithMaxResponseBodyBytes(16),
+		WithResponseObserver(func(method, url string, statusCode int, body []byte) {
+			assert.Equal(t, http.MethodGet, method)
+			assert.Equal(t, ts.URL+"/v1/organisation/accounts/"+uuid.Nil.String(), url)
+			statusCodes = append(statusCodes, statusCode)
+			bodies = append(bodies, string(body))
+		}),
+	)
+
+	organisationAccount, err := client.Fetch(context.Background(), uuid.Nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, AccountType, organisationAccount.Type)
+	assert.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK}, statusCodes)
+	assert.Equal(t, []string{`{"error_message"`, `{"data":{"type":`}, bodies)
+}
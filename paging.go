@@ -0,0 +1,419 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// default page size used when paging through all organisation accounts
+	// and the caller did not set one
+	defaultPagingPageSize = 100
+
+	// ErrStopWalking can be returned by the function passed to Walk to stop
+	// visiting organisation accounts without Walk returning an error.
+	ErrStopWalking = errors.New("stop walking")
+)
+
+// OrganisationAccountResult is a value sent by ListIter. It holds either
+// an organisation account or the error that stopped the iteration.
+type OrganisationAccountResult struct {
+	Account OrganisationAccount
+	Err     error
+}
+
+// ListResult is a page of organisation accounts returned by ListPage.
+type ListResult struct {
+	Accounts []OrganisationAccount
+
+	// NextPageToken is the token of the next page, to be given to the next ListPage
+	// call with PageToken. It is empty on the last page, or if the API does not
+	// support page tokens.
+	NextPageToken string
+
+	// TotalCount is the number of accounts selected by the list options across all
+	// pages, as read from the meta.total_count field of the response, or -1 if the
+	// API does not report it.
+	TotalCount int
+}
+
+// ListPage returns a single page of organisation accounts just like List, along with
+// the token of the next page, so that callers can page through the accounts with
+// PageToken without keeping track of page numbers. Unlike List, it sends all the IDs
+// given to FilterByIDs in a single request.
+func (c *Client) ListPage(ctx context.Context, loo ...ListOption) (ListResult, error) {
+	options := listOptions{}
+	for _, lo := range loo {
+		lo(&options)
+	}
+
+	if options.err != nil {
+		return ListResult{}, options.err
+	}
+
+	url, err := c.listURL(options)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	return c.listPage(ctx, url)
+}
+
+// ListParallel returns all organisation accounts just like ListAll, but requests up to
+// concurrency pages at a time (1 if concurrency is not positive), using the given page
+// size (or the default page size of the client, or 100, if it is not positive). It
+// fetches the first page to learn the total count of accounts, hence how many pages
+// there are, then requests all the others at once; if the API does not report a total,
+// it pages through the accounts one page at a time instead. Accounts are returned in
+// page order, and accounts appearing on two pages because of concurrent inserts are
+// only returned once. The first error cancels the requests left and is returned.
+func (c *Client) ListParallel(ctx context.Context, pageSize int, concurrency int, loo ...ListOption) ([]OrganisationAccount, error) {
+	if pageSize <= 0 {
+		pageSize = c.defaultPageSize
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPagingPageSize
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	pageOptions := func(pageNumber int) []ListOption {
+		return append(loo[:len(loo):len(loo)], PageNumberListOption(pageNumber), PageSizeListOption(pageSize))
+	}
+
+	first, err := c.ListPage(ctx, pageOptions(0)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if first.TotalCount < 0 {
+		organisationAccounts := first.Accounts
+		if len(first.Accounts) == pageSize {
+			rest, err := c.ListAll(ctx, pageOptions(1)...)
+			if err != nil {
+				return nil, err
+			}
+			organisationAccounts = append(organisationAccounts, rest...)
+		}
+
+		organisationAccounts = dedupeByID(organisationAccounts)
+
+		err = c.checkMaxListResults(len(organisationAccounts))
+		if err != nil {
+			return nil, err
+		}
+
+		return organisationAccounts, nil
+	}
+
+	err = c.checkMaxListResults(first.TotalCount)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount := (first.TotalCount + pageSize - 1) / pageSize
+	pages := make([][]OrganisationAccount, pageCount)
+	if pageCount > 0 {
+		pages[0] = first.Accounts
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for pageNumber := 1; pageNumber < pageCount; pageNumber++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(pageNumber int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// each goroutine writes to its own page, thus needs no locking
+			page, err := c.List(ctx, pageOptions(pageNumber)...)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			pages[pageNumber] = page
+		}(pageNumber)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var organisationAccounts []OrganisationAccount
+	for _, page := range pages {
+		organisationAccounts = append(organisationAccounts, page...)
+	}
+
+	organisationAccounts = dedupeByID(organisationAccounts)
+
+	// the total may have grown while the pages were fetched
+	err = c.checkMaxListResults(len(organisationAccounts))
+	if err != nil {
+		return nil, err
+	}
+
+	return organisationAccounts, nil
+}
+
+// checkMaxListResults returns an error wrapping ErrMaxResultsExceeded if the number of
+// organisation accounts collected is over the maximum set by WithMaxListResults.
+func (c *Client) checkMaxListResults(collected int) error {
+	if c.maxListResults > 0 && collected > c.maxListResults {
+		return fmt.Errorf("%w: collected %d accounts, more than %d", ErrMaxResultsExceeded, collected, c.maxListResults)
+	}
+	return nil
+}
+
+// dedupeByID removes the organisation accounts whose ID appeared earlier in the slice,
+// keeping the order of the others.
+func dedupeByID(organisationAccounts []OrganisationAccount) []OrganisationAccount {
+	seen := make(map[uuid.UUID]struct{}, len(organisationAccounts))
+	deduped := organisationAccounts[:0]
+	for _, account := range organisationAccounts {
+		if _, ok := seen[account.ID]; ok {
+			continue
+		}
+		seen[account.ID] = struct{}{}
+		deduped = append(deduped, account)
+	}
+
+	return deduped
+}
+
+// ListAll returns all organisation accounts, paging through them using the page
+// size set by PageSizeListOption (or the default page size of the client, or 100
+// if neither is set), starting at the page set by
+// PageNumberListOption. All other list options are applied to every page.
+func (c *Client) ListAll(ctx context.Context, loo ...ListOption) ([]OrganisationAccount, error) {
+	p := c.newPager(loo)
+
+	var organisationAccounts []OrganisationAccount
+	for {
+		page, err := p.next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			return organisationAccounts, nil
+		}
+
+		organisationAccounts = append(organisationAccounts, page...)
+	}
+}
+
+// ListIter works like ListAll, but instead of loading all organisation accounts in
+// memory it sends them one by one on the returned channel, fetching the next page only
+// once the consumer has read the current one. The first page is fetched before returning,
+// so that an invalid request is reported straight away; any later error is sent on the
+// channel as the last result.
+//
+// The channel is closed when all accounts were sent, after an error or once ctx is
+// cancelled or the client closed, thus cancelling ctx is enough to stop the iteration and
+// release its goroutine.
+func (c *Client) ListIter(ctx context.Context, loo ...ListOption) (<-chan OrganisationAccountResult, error) {
+	p := c.newPager(loo)
+
+	page, err := p.next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.lifecycle.start() {
+		return nil, ErrClientClosed
+	}
+
+	// the iteration stops once the client is closed, even if the consumer is gone
+	ctx, cancel := c.lifecycle.bind(ctx)
+
+	results := make(chan OrganisationAccountResult)
+
+	go func() {
+		defer c.lifecycle.done()
+		defer cancel()
+		defer close(results)
+
+		for page != nil {
+			for _, account := range page {
+				select {
+				case results <- OrganisationAccountResult{Account: account}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			page, err = p.next(ctx)
+			if err != nil {
+				select {
+				case results <- OrganisationAccountResult{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// Walk pages through all organisation accounts just like ListAll, calling fn for each
+// of them. If fn returns ErrStopWalking, Walk stops and returns nil; any other error
+// returned by fn stops the walk and is returned as is.
+func (c *Client) Walk(ctx context.Context, fn func(OrganisationAccount) error, loo ...ListOption) error {
+	p := c.newPager(loo)
+
+	for {
+		page, err := p.next(ctx)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			return nil
+		}
+
+		for _, account := range page {
+			err := fn(account)
+			if errors.Is(err, ErrStopWalking) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pager fetches organisation accounts page by page.
+type pager struct {
+	client     *Client
+	loo        []ListOption
+	pageNumber int
+	pageSize   int
+	fetched    bool
+	done       bool
+	collected  int
+}
+
+// newPager returns a pager starting at the page number and using the page size
+// set in loo, defaulting to the first page and to the default page size of the
+// client (or defaultPagingPageSize if the client has none).
+func (c *Client) newPager(loo []ListOption) *pager {
+	options := listOptions{}
+	for _, lo := range loo {
+		lo(&options)
+	}
+
+	pageSize := options.pageSize
+	if pageSize <= 0 {
+		pageSize = c.defaultPageSize
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPagingPageSize
+	}
+
+	return &pager{
+		client:     c,
+		loo:        loo,
+		pageNumber: options.pageNumber,
+		pageSize:   pageSize,
+	}
+}
+
+// next returns the next page of organisation accounts, or nil once there
+// are no more pages left.
+//
+// It waits for the pagination delay of the client between pages. If the API
+// still responds with 429 Too Many Requests once the client gave up retrying,
+// the page is requested one more time after waiting as long as the Retry-After
+// header of the response asked for.
+func (p *pager) next(ctx context.Context) ([]OrganisationAccount, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	if p.fetched {
+		if err := sleepContext(ctx, p.client.paginationDelay); err != nil {
+			return nil, err
+		}
+	}
+	p.fetched = true
+
+	loo := append(
+		p.loo[:len(p.loo):len(p.loo)],
+		PageNumberListOption(p.pageNumber),
+		PageSizeListOption(p.pageSize),
+	)
+
+	page, err := p.client.List(ctx, loo...)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0 {
+		if err := sleepContext(ctx, apiErr.RetryAfter); err != nil {
+			return nil, err
+		}
+
+		page, err = p.client.List(ctx, loo...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.pageNumber++
+
+	p.collected += len(page)
+	err = p.client.checkMaxListResults(p.collected)
+	if err != nil {
+		return nil, err
+	}
+
+	// a page that is not full is the last one
+	if len(page) < p.pageSize {
+		p.done = true
+	}
+
+	if len(page) == 0 {
+		return nil, nil
+	}
+
+	return page, nil
+}
+
+// sleepContext waits for d to elapse, returning early with the error of ctx
+// if it is cancelled in the meantime.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
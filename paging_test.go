@@ -0,0 +1,410 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newPagingServer returns a test server that serves the given organisation
+// accounts, honouring the page[number] and page[size] query parameters.
+func newPagingServer(accounts []OrganisationAccount) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageNumber, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		pageSize, err := strconv.Atoi(r.URL.Query().Get("page[size]"))
+		if err != nil {
+			pageSize = len(accounts)
+		}
+
+		var data struct {
+			Data []OrganisationAccount `json:"data"`
+		}
+
+		start := pageNumber * pageSize
+		if start < len(accounts) {
+			end := start + pageSize
+			if end > len(accounts) {
+				end = len(accounts)
+			}
+			data.Data = accounts[start:end]
+		}
+
+		_ = json.NewEncoder(w).Encode(&data)
+	}))
+}
+
+// newTestAccounts returns n organisation accounts with random IDs.
+func newTestAccounts(n int) []OrganisationAccount {
+	accounts := make([]OrganisationAccount, n)
+	for i := range accounts {
+		accounts[i] = OrganisationAccount{ID: uuid.New(), Type: "accounts"}
+	}
+
+	return accounts
+}
+
+func TestListAll(t *testing.T) {
+	testCases := []struct {
+		name     string
+		accounts []OrganisationAccount
+		pageSize int
+	}{
+		{
+			name:     "OK - no organisations",
+			pageSize: 2,
+		},
+		{
+			name:     "OK - last page is full",
+			accounts: newTestAccounts(4),
+			pageSize: 2,
+		},
+		{
+			name:     "OK - last page is not full",
+			accounts: newTestAccounts(5),
+			pageSize: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newPagingServer(tc.accounts)
+			defer ts.Close()
+
+			client := NewClient(ts.URL)
+
+			orgs, err := client.ListAll(context.Background(), PageSizeListOption(tc.pageSize))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.accounts, orgs)
+		})
+	}
+}
+
+func TestListIter(t *testing.T) {
+	accounts := newTestAccounts(5)
+
+	ts := newPagingServer(accounts)
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	t.Run("OK - all organisations are sent", func(t *testing.T) {
+		results, err := client.ListIter(context.Background(), PageSizeListOption(2))
+		assert.NoError(t, err)
+
+		var orgs []OrganisationAccount
+		for result := range results {
+			assert.NoError(t, result.Err)
+			orgs = append(orgs, result.Account)
+		}
+
+		assert.Equal(t, accounts, orgs)
+	})
+
+	t.Run("OK - cancelling the context closes the channel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		results, err := client.ListIter(ctx, PageSizeListOption(2))
+		assert.NoError(t, err)
+
+		<-results
+		cancel()
+
+		for range results {
+		}
+	})
+}
+
+func TestWalk(t *testing.T) {
+	accounts := newTestAccounts(5)
+	errWalk := errors.New("walk failed")
+
+	testCases := []struct {
+		name             string
+		fnErrAt          int
+		fnErr            error
+		expectedErr      error
+		expectedAccounts []OrganisationAccount
+	}{
+		{
+			name:             "OK - all organisations are visited",
+			fnErrAt:          -1,
+			expectedAccounts: accounts,
+		},
+		{
+			name:             "OK - walk is stopped",
+			fnErrAt:          2,
+			fnErr:            ErrStopWalking,
+			expectedAccounts: accounts[:3],
+		},
+		{
+			name:             "Not OK - walk is aborted",
+			fnErrAt:          3,
+			fnErr:            errWalk,
+			expectedErr:      errWalk,
+			expectedAccounts: accounts[:4],
+		},
+	}
+
+	ts := newPagingServer(accounts)
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var visited []OrganisationAccount
+
+			err := client.Walk(context.Background(), func(account OrganisationAccount) error {
+				visited = append(visited, account)
+				if len(visited)-1 == tc.fnErrAt {
+					return tc.fnErr
+				}
+				return nil
+			}, PageSizeListOption(2))
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedAccounts, visited)
+		})
+	}
+}
+
+func TestPaginationRateLimit(t *testing.T) {
+	accounts := newTestAccounts(4)
+	paging := newPagingServer(accounts)
+	defer paging.Close()
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// the second page is rate limited the first time it is requested
+		if requests == 2 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		paging.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	client := NewClient(
+		ts.URL,
+		WithBackoffStrategy(func() backoff.BackOff { return &backoff.StopBackOff{} }),
+		WithPaginationDelay(10*time.Millisecond),
+	)
+
+	start := time.Now()
+	orgs, err := client.ListAll(context.Background(), PageSizeListOption(2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, orgs)
+	assert.Equal(t, 4, requests)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second+20*time.Millisecond))
+
+	t.Run("Not OK - cancelled context interrupts the delay", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		client := NewClient(paging.URL, WithPaginationDelay(time.Hour))
+
+		err := client.Walk(ctx, func(OrganisationAccount) error {
+			cancel()
+			return nil
+		}, PageSizeListOption(2))
+
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestListPage(t *testing.T) {
+	accounts := newTestAccounts(5)
+
+	// pages of two accounts, identified by the index of their first account
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.URL.Query().Get("page[number]"))
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("page[token]"))
+		end := start + 2
+		if end > len(accounts) {
+			end = len(accounts)
+		}
+
+		body := map[string]interface{}{"data": accounts[start:end]}
+		if end < len(accounts) {
+			body["links"] = map[string]string{
+				"next": fmt.Sprintf("/v1/organisation/accounts?page[token]=%d&page[size]=2", end),
+			}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	var (
+		listed []OrganisationAccount
+		pages  int
+		token  string
+	)
+	for {
+		result, err := client.ListPage(context.Background(), PageToken(token))
+		assert.NoError(t, err)
+		pages++
+
+		listed = append(listed, result.Accounts...)
+		token = result.NextPageToken
+		if token == "" || pages > len(accounts) {
+			break
+		}
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, accounts, listed)
+}
+
+func TestPageTokenConflicts(t *testing.T) {
+	client := NewClient("http://localhost")
+
+	_, err := client.List(context.Background(), PageToken("abc"), PageNumberListOption(2))
+	assert.True(t, errors.Is(err, ErrConflictingListOptions))
+
+	_, err = client.ListPage(context.Background(), PageNumberListOption(2), PageToken("abc"))
+	assert.True(t, errors.Is(err, ErrConflictingListOptions))
+}
+
+func TestListParallel(t *testing.T) {
+	accounts := newTestAccounts(7)
+
+	// an insert shifting the accounts while they are paged through makes the last
+	// account of a page appear again on the next one
+	served := append(append(accounts[:4:4], accounts[3]), accounts[4:]...)
+
+	// a paging server that also reports the total count of accounts
+	withTotal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageNumber, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("page[size]"))
+
+		start, end := pageNumber*pageSize, (pageNumber+1)*pageSize
+		if end > len(served) {
+			end = len(served)
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": served[start:end],
+			"meta": map[string]int{"total_count": len(served)},
+		})
+	}))
+	defer withTotal.Close()
+
+	withoutTotal := newPagingServer(served)
+	defer withoutTotal.Close()
+
+	for _, url := range []string{withTotal.URL, withoutTotal.URL} {
+		client := NewClient(url)
+
+		listed, err := client.ListParallel(context.Background(), 2, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, accounts, listed)
+
+		listed, err = client.ListParallel(context.Background(), 10, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, accounts, listed)
+	}
+
+	page, err := NewClient(withTotal.URL).ListPage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, len(served), page.TotalCount)
+
+	page, err = NewClient(withoutTotal.URL).ListPage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, -1, page.TotalCount)
+}
+
+func TestListParallelError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page[number]") == "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error_message":"invalid page"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": newTestAccounts(2),
+			"meta": map[string]int{"total_count": 10},
+		})
+	}))
+	defer ts.Close()
+
+	_, err := NewClient(ts.URL).ListParallel(context.Background(), 2, 2)
+	assert.True(t, errors.Is(err, ErrBadRequest))
+}
+
+func TestWithMaxListResults(t *testing.T) {
+	accounts := newTestAccounts(10)
+
+	ts := newPagingServer(accounts)
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		maxResults  int
+		expectedErr error
+	}{
+		{
+			name:       "OK - no maximum",
+			maxResults: 0,
+		},
+		{
+			name:       "OK - exactly the maximum",
+			maxResults: 10,
+		},
+		{
+			name:        "Not OK - one over the maximum",
+			maxResults:  9,
+			expectedErr: ErrMaxResultsExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(ts.URL, WithMaxListResults(tc.maxResults))
+
+			listed, err := client.ListAll(context.Background(), PageSizeListOption(3))
+			parallel, parallelErr := client.ListParallel(context.Background(), 3, 2)
+
+			var walked int
+			walkErr := client.Walk(context.Background(), func(OrganisationAccount) error {
+				walked++
+				return nil
+			}, PageSizeListOption(3))
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+				assert.Nil(t, listed)
+				assert.True(t, errors.Is(parallelErr, tc.expectedErr))
+				assert.Nil(t, parallel)
+				assert.True(t, errors.Is(walkErr, tc.expectedErr))
+				// the page going over the maximum is not walked
+				assert.Equal(t, 9, walked)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, accounts, listed)
+			assert.NoError(t, parallelErr)
+			assert.Equal(t, accounts, parallel)
+			assert.NoError(t, walkErr)
+			assert.Equal(t, 10, walked)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package form3
+
+import (
+	"context"
+	"sync"
+)
+
+// ParallelCreate creates the given organisation accounts using at most concurrency
+// requests at a time (1 if concurrency is not positive). It does not stop at the first
+// error: both returned slices have the same length as accounts, and their i-th elements
+// hold the outcome of creating the i-th account, i.e. either the created account and a
+// nil error, or an empty account and the error.
+func (c *Client) ParallelCreate(ctx context.Context, accounts []OrganisationAccount, concurrency int) ([]OrganisationAccount, []error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// once the client is closed, the accounts left fail with ErrClientClosed
+	if c.lifecycle.start() {
+		defer c.lifecycle.done()
+	}
+
+	created := make([]OrganisationAccount, len(accounts))
+	errs := make([]error, len(accounts))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range accounts {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// each goroutine writes to its own index, thus needs no locking
+			created[i], errs[i] = c.Create(ctx, accounts[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	return created, errs
+}
@@ -0,0 +1,70 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelCreate(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		var body struct {
+			Data OrganisationAccount `json:"data"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		// later accounts finish first, and every third one is rejected
+		time.Sleep(time.Duration(10-body.Data.Version) * time.Millisecond)
+		if body.Data.Version%3 == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error_message":"invalid account"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&body)
+	}))
+	defer ts.Close()
+
+	accounts := make([]OrganisationAccount, 10)
+	for i := range accounts {
+		accounts[i] = OrganisationAccount{ID: uuid.New(), Version: i}
+	}
+
+	client := NewClient(ts.URL)
+
+	created, errs := client.ParallelCreate(context.Background(), accounts, 3)
+
+	assert.Len(t, created, len(accounts))
+	assert.Len(t, errs, len(accounts))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+
+	for i := range accounts {
+		if i%3 == 0 {
+			assert.Error(t, errs[i])
+			assert.Equal(t, OrganisationAccount{}, created[i])
+			continue
+		}
+
+		assert.NoError(t, errs[i])
+		assert.Equal(t, accounts[i].ID, created[i].ID)
+	}
+}
@@ -0,0 +1,127 @@
+package form3
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// DiffAccounts returns the JSON Merge Patch (RFC 7396) turning before into after, i.e. a
+// JSON object holding only the fields that changed, e.g. to update an account fetched
+// earlier without resetting the fields the caller did not change. Fields set in before
+// but not in after are set to null, and arrays that changed are replaced as a whole.
+// It returns an empty object if the accounts do not differ.
+func DiffAccounts(before, after OrganisationAccount) (json.RawMessage, error) {
+	beforeObject, err := toJSONObject(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterObject, err := toJSONObject(after)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(beforeObject, afterObject))
+}
+
+// toJSONObject encodes v as a JSON object and decodes it back into a map, keeping numbers
+// as they were encoded.
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	err = decoder.Decode(&object)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// mergePatch returns the merge patch turning the before object into the after one.
+func mergePatch(before, after map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+
+	for key, afterValue := range after {
+		beforeValue, ok := before[key]
+		if ok && reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		beforeObject, beforeIsObject := beforeValue.(map[string]interface{})
+		afterObject, afterIsObject := afterValue.(map[string]interface{})
+		if beforeIsObject && afterIsObject {
+			patch[key] = mergePatch(beforeObject, afterObject)
+			continue
+		}
+
+		patch[key] = afterValue
+	}
+
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			patch[key] = nil
+		}
+	}
+
+	return patch
+}
+
+// MergeAttributes returns a copy of base with the fields set in patch overwriting
+// its own, like a JSON Merge Patch applied to the attributes: a field is set if it
+// is non-zero, a pointer if it is non-nil and a slice if it is non-empty. As a
+// consequence, patch cannot reset a field of base to its zero value.
+func MergeAttributes(base, patch OrganisationAccountAttributes) OrganisationAccountAttributes {
+	merged := base
+
+	if patch.Country != "" {
+		merged.Country = patch.Country
+	}
+	if patch.BaseCurrency != "" {
+		merged.BaseCurrency = patch.BaseCurrency
+	}
+	if patch.AccountNumber != nil {
+		merged.AccountNumber = patch.AccountNumber
+	}
+	if patch.BankID != "" {
+		merged.BankID = patch.BankID
+	}
+	if patch.BankIDCode != "" {
+		merged.BankIDCode = patch.BankIDCode
+	}
+	if patch.BIC != nil {
+		merged.BIC = patch.BIC
+	}
+	if patch.IBAN != nil {
+		merged.IBAN = patch.IBAN
+	}
+	if len(patch.Name) > 0 {
+		merged.Name = patch.Name
+	}
+	if len(patch.AlternativeNames) > 0 {
+		merged.AlternativeNames = patch.AlternativeNames
+	}
+	if patch.AccountClassification != "" {
+		merged.AccountClassification = patch.AccountClassification
+	}
+	if patch.JointAccount != nil {
+		merged.JointAccount = patch.JointAccount
+	}
+	if patch.AccountMatchingOptOut {
+		merged.AccountMatchingOptOut = true
+	}
+	if patch.SecondaryIdentification != nil {
+		merged.SecondaryIdentification = patch.SecondaryIdentification
+	}
+	if patch.Switched != nil {
+		merged.Switched = patch.Switched
+	}
+
+	return merged
+}
@@ -0,0 +1,131 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAccounts(t *testing.T) {
+	before := OrganisationAccount{
+		ID:      uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		Type:    AccountType,
+		Version: 1,
+		Attributes: OrganisationAccountAttributes{
+			Country: CountryGB,
+			BIC:     String("NWBKGB22"),
+			Name:    []string{"Jane Doe"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		change   func(o *OrganisationAccount)
+		expected string
+	}{
+		{
+			name:     "no change",
+			change:   func(o *OrganisationAccount) {},
+			expected: `{}`,
+		},
+		{
+			name: "changed attributes",
+			change: func(o *OrganisationAccount) {
+				o.Attributes.Name = []string{"Jane Doe", "J. Doe"}
+				o.Attributes.BaseCurrency = "GBP"
+			},
+			expected: `{"attributes":{"name":["Jane Doe","J. Doe"],"base_currency":"GBP"}}`,
+		},
+		{
+			name: "removed optional attribute",
+			change: func(o *OrganisationAccount) {
+				o.Attributes.BIC = nil
+				o.Version = 2
+			},
+			expected: `{"version":2,"attributes":{"bic":null}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			after := before
+			after.Attributes.Name = append([]string(nil), before.Attributes.Name...)
+			tc.change(&after)
+
+			patch, err := DiffAccounts(before, after)
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(patch))
+		})
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	base := OrganisationAccountAttributes{
+		Country:          "GB",
+		BaseCurrency:     "GBP",
+		AccountNumber:    String("41426819"),
+		BankID:           "400300",
+		BankIDCode:       "GBDSC",
+		BIC:              String("NWBKGB22"),
+		Name:             []string{"Samantha Holder"},
+		AlternativeNames: []string{"Sam Holder"},
+		JointAccount:     Bool(false),
+	}
+
+	full := OrganisationAccountAttributes{
+		Country:                 "FR",
+		BaseCurrency:            "EUR",
+		AccountNumber:           String("0500013M026"),
+		BankID:                  "20041",
+		BankIDCode:              "FR",
+		BIC:                     String("BNPAFRPP"),
+		IBAN:                    String("FR1420041010050500013M02606"),
+		Name:                    []string{"Jean Dupont"},
+		AlternativeNames:        []string{"J. Dupont"},
+		AccountClassification:   ClassificationBusiness,
+		JointAccount:            Bool(true),
+		AccountMatchingOptOut:   true,
+		SecondaryIdentification: String("A1B2C3D4"),
+		Switched:                Bool(true),
+	}
+
+	tests := map[string]struct {
+		patch    OrganisationAccountAttributes
+		expected OrganisationAccountAttributes
+	}{
+		"empty patch": {
+			patch:    OrganisationAccountAttributes{},
+			expected: base,
+		},
+		"partial patch": {
+			patch: OrganisationAccountAttributes{
+				BIC:          String("BARCGB22"),
+				Name:         []string{"Samantha Jones"},
+				JointAccount: Bool(true),
+			},
+			expected: func() OrganisationAccountAttributes {
+				expected := base
+				expected.BIC = String("BARCGB22")
+				expected.Name = []string{"Samantha Jones"}
+				expected.JointAccount = Bool(true)
+				return expected
+			}(),
+		},
+		"empty slices do not overwrite": {
+			patch:    OrganisationAccountAttributes{Name: []string{}, AlternativeNames: nil},
+			expected: base,
+		},
+		"full patch": {
+			patch:    full,
+			expected: full,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MergeAttributes(base, tc.patch))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package form3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// ErrProfileNotFound is returned when asking a ProfileStore for a profile it does not hold.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile is the configuration of a client for a given environment, e.g. dev,
+// staging or prod. Zero values leave the client defaults untouched.
+type Profile struct {
+	BaseURL         string
+	APIKey          string
+	APIVersion      string
+	Timeout         time.Duration
+	DefaultPageSize int
+}
+
+// options returns the client options configuring a client after the profile.
+func (p Profile) options() []ClientOption {
+	var coo []ClientOption
+	if p.APIKey != "" {
+		coo = append(coo, WithAPIKey(p.APIKey))
+	}
+	if p.APIVersion != "" {
+		coo = append(coo, WithAPIVersion(p.APIVersion))
+	}
+	if p.Timeout != 0 {
+		coo = append(coo, WithTimeout(p.Timeout))
+	}
+	if p.DefaultPageSize != 0 {
+		coo = append(coo, WithDefaultPageSize(p.DefaultPageSize))
+	}
+	return coo
+}
+
+// profileJSON is the representation of a profile inside a profiles file, where the
+// timeout is written as a duration string, e.g. "30s".
+type profileJSON struct {
+	BaseURL         string `json:"base_url"`
+	APIKey          string `json:"api_key,omitempty"`
+	APIVersion      string `json:"api_version,omitempty"`
+	Timeout         string `json:"timeout,omitempty"`
+	DefaultPageSize int    `json:"default_page_size,omitempty"`
+}
+
+// ProfileStore holds named profiles. It is safe for concurrent use.
+type ProfileStore struct {
+	mu       sync.RWMutex
+	profiles map[string]Profile
+}
+
+// NewProfileStore returns an empty profile store.
+func NewProfileStore() *ProfileStore {
+	return &ProfileStore{profiles: map[string]Profile{}}
+}
+
+// LoadProfilesFromFile returns a profile store holding the profiles read from the
+// JSON file at path, which maps profile names to their configuration, e.g.
+//
+//	{
+//	  "dev": {"base_url": "http://localhost:8080", "timeout": "5s"},
+//	  "prod": {"base_url": "https://api.form3.tech", "api_key": "...", "timeout": "30s"}
+//	}
+func LoadProfilesFromFile(path string) (*ProfileStore, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]profileJSON
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing profiles file %s: %w", path, err)
+	}
+
+	store := NewProfileStore()
+	for name, rp := range raw {
+		profile := Profile{
+			BaseURL:         rp.BaseURL,
+			APIKey:          rp.APIKey,
+			APIVersion:      rp.APIVersion,
+			DefaultPageSize: rp.DefaultPageSize,
+		}
+
+		if rp.Timeout != "" {
+			profile.Timeout, err = time.ParseDuration(rp.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("parsing timeout of profile %s: %w", name, err)
+			}
+		}
+
+		store.Set(name, profile)
+	}
+
+	return store, nil
+}
+
+// Set stores the profile under the given name, replacing any profile already stored under it.
+func (s *ProfileStore) Set(name string, profile Profile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.profiles[name] = profile
+}
+
+// Get returns the profile stored under the given name.
+func (s *ProfileStore) Get(name string) (Profile, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, ok := s.profiles[name]
+
+	return profile, ok
+}
+
+// NewClientFromProfile returns a new client configured after the profile stored under
+// the given name. The given client options are applied after the profile, thus take
+// precedence over it.
+func NewClientFromProfile(store *ProfileStore, name string, coo ...ClientOption) (*Client, error) {
+	profile, ok := store.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	if profile.BaseURL == "" {
+		return nil, fmt.Errorf("profile %s has no base URL", name)
+	}
+
+	return NewClient(profile.BaseURL, append(profile.options(), coo...)...), nil
+}
@@ -0,0 +1,92 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProfilesFromFile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contents    string
+		expected    map[string]Profile
+		expectedErr bool
+	}{
+		{
+			name:     "valid profiles",
+			contents: `{"dev": {"base_url": "http://localhost:8080"}, "prod": {"base_url": "https://api.form3.tech", "api_key": "secret", "timeout": "30s", "default_page_size": 50}}`,
+			expected: map[string]Profile{
+				"dev":  {BaseURL: "http://localhost:8080"},
+				"prod": {BaseURL: "https://api.form3.tech", APIKey: "secret", Timeout: 30 * time.Second, DefaultPageSize: 50},
+			},
+		},
+		{
+			name:        "malformed JSON",
+			contents:    `{"dev": `,
+			expectedErr: true,
+		},
+		{
+			name:        "malformed timeout",
+			contents:    `{"dev": {"base_url": "http://localhost:8080", "timeout": "soon"}}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.json")
+			if err := ioutil.WriteFile(path, []byte(tc.contents), 0600); err != nil {
+				t.Fatalf("could not write profiles file: %v", err)
+			}
+
+			store, err := LoadProfilesFromFile(path)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			for name, expected := range tc.expected {
+				profile, ok := store.Get(name)
+				assert.True(t, ok)
+				assert.Equal(t, expected, profile)
+			}
+		})
+	}
+}
+
+func TestNewClientFromProfile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	store := NewProfileStore()
+	store.Set("staging", Profile{BaseURL: ts.URL, APIKey: "secret", Timeout: time.Second})
+	store.Set("broken", Profile{})
+
+	client, err := NewClientFromProfile(store, "staging")
+	if err != nil {
+		t.Fatalf("could not create client from profile: %v", err)
+	}
+	assert.Equal(t, time.Second, client.httpClient.load().Timeout)
+
+	_, err = client.Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+
+	_, err = NewClientFromProfile(store, "prod")
+	assert.True(t, errors.Is(err, ErrProfileNotFound))
+
+	_, err = NewClientFromProfile(store, "broken")
+	assert.Error(t, err)
+}
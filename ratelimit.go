@@ -0,0 +1,115 @@
+package form3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket rate limiter, safe for concurrent use. It holds up to
+// burst tokens and is refilled at rate tokens per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full token bucket refilled at rate tokens per second and
+// holding up to burst tokens.
+func newTokenBucket(rate, burst float64) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst}
+}
+
+// refill adds the tokens accumulated since the last call. It must be called with
+// the lock held.
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+}
+
+// take takes a token from the bucket, returning false if there is none left.
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+
+	return true
+}
+
+// reserve takes a token from the bucket, even if there is none left yet, and returns
+// how long to wait for it to become available.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateSchedule holds a token bucket per UTC hour with a rate limit.
+type rateSchedule map[int]*tokenBucket
+
+// newRateSchedule returns the schedule enforcing the given rates, in requests per
+// second, keyed by UTC hour. It returns an error if an hour or a rate is invalid.
+func newRateSchedule(rateByHour map[int]float64) (rateSchedule, error) {
+	schedule := make(rateSchedule, len(rateByHour))
+	for hour, rate := range rateByHour {
+		if hour < 0 || hour > 23 {
+			return nil, fmt.Errorf("invalid rate limit schedule: hour %d is not between 0 and 23", hour)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid rate limit schedule: rate %v of hour %d is not positive", rate, hour)
+		}
+
+		burst := rate
+		if burst < 1 {
+			burst = 1
+		}
+		schedule[hour] = newTokenBucket(rate, burst)
+	}
+
+	return schedule, nil
+}
+
+// wait waits until a request is allowed by the rate limit of the current UTC hour,
+// if any, returning early with the error of ctx if it is cancelled in the meantime.
+func (s rateSchedule) wait(ctx context.Context, now time.Time) error {
+	bucket, ok := s[now.UTC().Hour()]
+	if !ok {
+		return nil
+	}
+
+	return sleepContext(ctx, bucket.reserve(now))
+}
+
+// refilled returns a schedule enforcing the same rate limits with full buckets.
+func (s rateSchedule) refilled() rateSchedule {
+	refilled := make(rateSchedule, len(s))
+	for hour, bucket := range s {
+		refilled[hour] = newTokenBucket(bucket.rate, bucket.burst)
+	}
+
+	return refilled
+}
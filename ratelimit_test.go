@@ -0,0 +1,134 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucketTake(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(2, 3)
+
+	// a full bucket allows a burst
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.take(start))
+	}
+	assert.False(t, b.take(start))
+
+	// refilled at 2 tokens per second
+	assert.False(t, b.take(start.Add(400*time.Millisecond)))
+	assert.True(t, b.take(start.Add(500*time.Millisecond)))
+	assert.False(t, b.take(start.Add(500*time.Millisecond)))
+
+	// never above the burst size
+	later := start.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.take(later))
+	}
+	assert.False(t, b.take(later))
+
+	// a clock going backwards does not refill the bucket
+	assert.False(t, b.take(start))
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(2, 2)
+
+	assert.Zero(t, b.reserve(start))
+	assert.Zero(t, b.reserve(start))
+
+	// each reservation past the burst waits for one more token
+	assert.Equal(t, 500*time.Millisecond, b.reserve(start))
+	assert.Equal(t, time.Second, b.reserve(start))
+	assert.Equal(t, 500*time.Millisecond, b.reserve(start.Add(time.Second)))
+}
+
+func TestWithScheduledRateLimit(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		now         time.Time
+		rateByHour  map[int]float64
+		minDuration time.Duration
+		expectedErr bool
+	}{
+		{
+			name:        "OK - requests are throttled during a scheduled hour",
+			now:         time.Date(2021, 1, 1, 10, 30, 0, 0, time.UTC),
+			rateByHour:  map[int]float64{10: 20},
+			minDuration: 150 * time.Millisecond,
+		},
+		{
+			name:       "OK - requests are not throttled outside the scheduled hours",
+			now:        time.Date(2021, 1, 1, 11, 30, 0, 0, time.UTC),
+			rateByHour: map[int]float64{10: 0.1},
+		},
+		{
+			name:        "OK - the hour is read in UTC",
+			now:         time.Date(2021, 1, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			rateByHour:  map[int]float64{10: 20},
+			minDuration: 150 * time.Millisecond,
+		},
+		{
+			name:        "Not OK - hour out of range",
+			rateByHour:  map[int]float64{24: 10},
+			expectedErr: true,
+		},
+		{
+			name:        "Not OK - rate is not positive",
+			rateByHour:  map[int]float64{10: 0},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = 0
+
+			start, now := time.Now(), tc.now
+
+			client := NewClient(ts.URL, WithScheduledRateLimit(tc.rateByHour))
+			client.clockFunc = func() time.Time { return now.Add(time.Since(start)) }
+
+			for i := 0; i < 23; i++ {
+				_, err := client.List(context.Background())
+				if tc.expectedErr {
+					assert.Error(t, err)
+					assert.Zero(t, requests)
+					return
+				}
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, 23, requests)
+			// at 20 requests per second, the 3 requests past the burst wait for 50ms each
+			assert.GreaterOrEqual(t, int64(time.Since(start)), int64(tc.minDuration))
+		})
+	}
+
+	t.Run("Not OK - cancelled context interrupts the wait", func(t *testing.T) {
+		client := NewClient(ts.URL, WithScheduledRateLimit(map[int]float64{10: 0.001}))
+		client.clockFunc = func() time.Time { return time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC) }
+
+		_, err := client.List(context.Background())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = client.List(ctx)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -0,0 +1,71 @@
+package form3
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationResult holds the differences found by Reconcile between local records
+// of organisation accounts and the accounts held by the Form3 API.
+type ReconciliationResult struct {
+	// OnlyLocal holds the local accounts whose ID the API does not know.
+	OnlyLocal []OrganisationAccount
+
+	// OnlyRemote holds the accounts of the API whose ID is not found locally.
+	OnlyRemote []OrganisationAccount
+
+	// Divergent holds the accounts found on both sides, but whose data differ.
+	Divergent []AccountDivergence
+}
+
+// AccountDivergence holds the local and the remote versions of an organisation account
+// whose data differ.
+type AccountDivergence struct {
+	Local  OrganisationAccount
+	Remote OrganisationAccount
+}
+
+// Reconcile compares local records of organisation accounts to the accounts held by the
+// Form3 API, as returned by ListAll with the given list options, e.g. to only reconcile
+// the accounts of an organisation. Accounts are matched by ID and compared with
+// OrganisationAccount.Equal. Each part of the result follows the order of the side it
+// is taken from, the local one for divergent accounts. If several local records share
+// an ID, only the last one is reconciled, at the position of the first one.
+func (c *Client) Reconcile(ctx context.Context, local []OrganisationAccount, loo ...ListOption) (ReconciliationResult, error) {
+	remote, err := c.ListAll(ctx, loo...)
+	if err != nil {
+		return ReconciliationResult{}, err
+	}
+
+	localByID := AccountsByID(local)
+	remoteByID := AccountsByID(remote)
+
+	var result ReconciliationResult
+	reconciled := make(map[uuid.UUID]struct{}, len(localByID))
+	for _, account := range local {
+		if _, ok := reconciled[account.ID]; ok {
+			continue
+		}
+		reconciled[account.ID] = struct{}{}
+		account = localByID[account.ID]
+
+		remoteAccount, ok := remoteByID[account.ID]
+		if !ok {
+			result.OnlyLocal = append(result.OnlyLocal, account)
+			continue
+		}
+
+		if !account.Equal(remoteAccount) {
+			result.Divergent = append(result.Divergent, AccountDivergence{Local: account, Remote: remoteAccount})
+		}
+	}
+
+	for _, account := range remote {
+		if _, ok := localByID[account.ID]; !ok {
+			result.OnlyRemote = append(result.OnlyRemote, account)
+		}
+	}
+
+	return result, nil
+}
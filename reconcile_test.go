@@ -0,0 +1,52 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile(t *testing.T) {
+	accounts := newTestAccounts(5)
+
+	remote := []OrganisationAccount{accounts[0], accounts[1], accounts[2], accounts[4]}
+
+	changed := accounts[1]
+	changed.Version = 2
+
+	stale := accounts[2]
+	stale.Version = 5
+
+	local := []OrganisationAccount{
+		accounts[0],
+		stale,
+		changed,
+		accounts[3],
+		// the last record of an account is the one reconciled
+		accounts[2],
+	}
+
+	ts := newPagingServer(remote)
+	defer ts.Close()
+
+	result, err := NewClient(ts.URL).Reconcile(context.Background(), local)
+	assert.NoError(t, err)
+	assert.Equal(t, ReconciliationResult{
+		OnlyLocal:  []OrganisationAccount{accounts[3]},
+		OnlyRemote: []OrganisationAccount{accounts[4]},
+		Divergent:  []AccountDivergence{{Local: changed, Remote: accounts[1]}},
+	}, result)
+}
+
+func TestReconcileError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	_, err := NewClient(ts.URL).Reconcile(context.Background(), newTestAccounts(1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// maxRelatedFetches is the number of accounts FetchRelated fetches at a time.
+const maxRelatedFetches = 8
+
+// FetchRelated fetches, in parallel, the organisation accounts the given account is
+// related to through the relationship named rel, in the order they are referenced in.
+// It returns no accounts if the account has no such relationship, and an error if the
+// relationship references resources other than accounts or if any fetch fails, in
+// which case the fetches still in flight are cancelled.
+func (c *Client) FetchRelated(ctx context.Context, account OrganisationAccount, rel string) ([]OrganisationAccount, error) {
+	refs := account.Relationships[rel].Data
+
+	for _, ref := range refs {
+		if ref.Type != AccountType {
+			return nil, fmt.Errorf("relationship %s references a resource of type %q, not an account", rel, ref.Type)
+		}
+	}
+
+	related := make([]OrganisationAccount, len(refs))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		sem      = make(chan struct{}, maxRelatedFetches)
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		stopErr  error
+	)
+	for i, ref := range refs {
+		sem <- struct{}{}
+
+		// no need to start more fetches once one failed or the caller gave up
+		if stopErr = ctx.Err(); stopErr != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, ref RelationshipRef) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			// each goroutine writes to its own account, thus needs no locking
+			organisationAccount, err := c.Fetch(ctx, ref.ID)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("fetching account %s related through %s: %w", ref.ID, rel, err)
+					cancel()
+				})
+				return
+			}
+			related[i] = organisationAccount
+		}(i, ref)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// some accounts were not fetched, although none of the fetches failed
+	if stopErr != nil {
+		return nil, stopErr
+	}
+
+	return related, nil
+}
+
+// attachIncluded sets the Included field of the organisation account from the resources
+// embedded in the response it was received in, keeping those related to it through its
+// relationships.
+func attachIncluded(organisationAccount *OrganisationAccount, included []json.RawMessage) error {
+	if len(included) == 0 || len(organisationAccount.Relationships) == 0 {
+		return nil
+	}
+
+	resources := make(map[RelationshipRef]json.RawMessage, len(included))
+	for _, resource := range included {
+		// resources that cannot be referenced by a relationship are left out
+		var ref RelationshipRef
+		if json.Unmarshal(resource, &ref) == nil {
+			resources[ref] = resource
+		}
+	}
+
+	for name, relationship := range organisationAccount.Relationships {
+		var related []json.RawMessage
+		for _, ref := range relationship.Data {
+			if resource, ok := resources[ref]; ok {
+				related = append(related, resource)
+			}
+		}
+		if len(related) == 0 {
+			continue
+		}
+
+		b, err := json.Marshal(related)
+		if err != nil {
+			return err
+		}
+
+		if organisationAccount.Included == nil {
+			organisationAccount.Included = map[string]json.RawMessage{}
+		}
+		organisationAccount.Included[name] = b
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchRelated(t *testing.T) {
+	first, second, missing := uuid.New(), uuid.New(), uuid.New()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if id == missing.String() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"` + id + `","type":"accounts"}}`))
+	}))
+	defer ts.Close()
+
+	account := OrganisationAccount{
+		ID: uuid.New(),
+		Relationships: map[string]RelationshipData{
+			"linked_accounts": {Data: []RelationshipRef{{ID: first, Type: AccountType}, {ID: second, Type: AccountType}}},
+			"broken":          {Data: []RelationshipRef{{ID: first, Type: AccountType}, {ID: missing, Type: AccountType}}},
+			"owner":           {Data: []RelationshipRef{{ID: uuid.New(), Type: "organisations"}}},
+		},
+	}
+
+	client := NewClient(ts.URL)
+	ctx := context.Background()
+
+	related, err := client.FetchRelated(ctx, account, "linked_accounts")
+	assert.NoError(t, err)
+	if assert.Len(t, related, 2) {
+		assert.Equal(t, first, related[0].ID)
+		assert.Equal(t, second, related[1].ID)
+	}
+
+	related, err = client.FetchRelated(ctx, account, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, related)
+
+	_, err = client.FetchRelated(ctx, account, "broken")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = client.FetchRelated(ctx, account, "owner")
+	assert.Error(t, err)
+}
+
+func TestIncludeRelated(t *testing.T) {
+	const (
+		account      = `{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc","type":"accounts","relationships":{"organisation":{"data":[{"id":"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c","type":"organisations"}]}}}`
+		organisation = `{"id":"eb0bd6f5-c3f5-44b2-b677-acd23cdde73c","type":"organisations","attributes":{"name":"Acme"}}`
+		unrelated    = `{"id":"5d8a4f1c-8ee9-4f6b-9d3b-7d2f8f1e6a10","type":"organisations"}`
+	)
+
+	var include []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		include = append(include, r.URL.Query().Get("include"))
+
+		data := account
+		if r.URL.Path == "/v1/organisation/accounts" {
+			data = "[" + account + "]"
+		}
+		_, _ = w.Write([]byte(`{"data":` + data + `,"included":[` + organisation + `,` + unrelated + `]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+	expected := map[string]json.RawMessage{"organisation": json.RawMessage("[" + organisation + "]")}
+
+	fetched, err := client.Fetch(context.Background(), uuid.New(), IncludeRelatedFetch("organisation"))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, fetched.Included)
+
+	listed, err := client.List(context.Background(), IncludeRelated("organisation", "bank"))
+	assert.NoError(t, err)
+	if assert.Len(t, listed, 1) {
+		assert.Equal(t, expected, listed[0].Included)
+	}
+
+	assert.Equal(t, []string{"organisation", "organisation,bank"}, include)
+}
+
+func TestFetchRelatedStopsAtFirstError(t *testing.T) {
+	var inFlight, maxInFlight, requests int32
+	missing := uuid.New()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		if strings.HasSuffix(r.URL.Path, missing.String()) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"data":{"type":"accounts"}}`))
+	}))
+	defer ts.Close()
+
+	refs := []RelationshipRef{{ID: missing, Type: AccountType}}
+	for i := 0; i < 10*maxRelatedFetches; i++ {
+		refs = append(refs, RelationshipRef{ID: uuid.New(), Type: AccountType})
+	}
+	account := OrganisationAccount{
+		Relationships: map[string]RelationshipData{"linked_accounts": {Data: refs}},
+	}
+
+	_, err := NewClient(ts.URL).FetchRelated(context.Background(), account, "linked_accounts")
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(maxRelatedFetches))
+	assert.Less(t, atomic.LoadInt32(&requests), int32(len(refs)))
+}
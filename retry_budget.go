@@ -0,0 +1,34 @@
+package form3
+
+import (
+	"time"
+)
+
+// RetryBudget caps the rate of retries across all the clients sharing it, e.g. one
+// client per worker goroutine, so that the retry traffic of a whole process stays
+// bounded whatever the number of clients. It is safe for concurrent use.
+type RetryBudget struct {
+	bucket *tokenBucket
+
+	// clockFunc returns the current time
+	clockFunc func() time.Time
+}
+
+// NewRetryBudget returns a retry budget allowing up to maxAttemptsPerSecond retries per
+// second on average, with bursts of up to as many retries (at least one).
+func NewRetryBudget(maxAttemptsPerSecond float64) *RetryBudget {
+	burst := maxAttemptsPerSecond
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RetryBudget{
+		bucket:    newTokenBucket(maxAttemptsPerSecond, burst),
+		clockFunc: time.Now,
+	}
+}
+
+// allow reports whether a retry fits in the budget, spending it if so.
+func (b *RetryBudget) allow() bool {
+	return b.bucket.take(b.clockFunc())
+}
@@ -0,0 +1,73 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetryBudget(t *testing.T) {
+	var attempts int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// a clock that stands still, so that the budget is never refilled
+	budget := NewRetryBudget(2)
+	now := time.Now()
+	budget.clockFunc = func() time.Time { return now }
+
+	newClient := func() *Client {
+		return NewClient(
+			ts.URL,
+			WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 5)),
+			WithRetryBudget(budget),
+		)
+	}
+
+	// the first client spends the whole budget
+	_, err := newClient().List(context.Background())
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.True(t, errors.Is(err, &APIError{StatusCode: http.StatusServiceUnavailable}))
+	assert.Equal(t, 3, attempts)
+
+	// the second client shares the budget, thus is not allowed to retry
+	_, err = newClient().List(context.Background())
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 1, retryErr.Attempts)
+	assert.Equal(t, 4, attempts)
+}
+
+func TestRetryBudgetOnlySpentOnRetries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	budget := NewRetryBudget(3)
+	now := time.Now()
+	budget.clockFunc = func() time.Time { return now }
+
+	client := NewClient(
+		ts.URL,
+		WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)),
+		WithRetryBudget(budget),
+	)
+
+	// the back-off gives up after 2 retries, which spend 2 tokens: the last attempt,
+	// not followed by any retry, spends none
+	_, err := client.List(context.Background())
+	var retryErr *RetryError
+	assert.True(t, errors.As(err, &retryErr))
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.Equal(t, 1.0, budget.bucket.tokens)
+}
@@ -0,0 +1,80 @@
+package form3
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryEvent describes a failed attempt at performing a request that is about to be retried.
+type RetryEvent struct {
+	// Attempt is the 1-based number of the failed attempt.
+	Attempt int
+	Method  string
+	URL     string
+	// StatusCode is the status of the response that made the attempt fail, or 0 if
+	// it failed with a network error.
+	StatusCode int
+	// Err is the network error that made the attempt fail, if any. See
+	// WithRetryOnNetworkError.
+	Err error
+	// Elapsed is the time spent on the request so far, across all its attempts.
+	Elapsed time.Duration
+}
+
+// RetryObserver is a function observing retry events.
+type RetryObserver func(event RetryEvent)
+
+// CancelFunc undoes a registration, e.g. of a retry observer. Calling it more than
+// once has no further effect.
+type CancelFunc func()
+
+// retryBus holds the retry observers registered with OnRetry.
+type retryBus struct {
+	mu        sync.RWMutex
+	nextID    int
+	observers []registeredRetryObserver
+}
+
+type registeredRetryObserver struct {
+	id int
+	fn RetryObserver
+}
+
+// OnRetry registers fn to be called, synchronously and before waiting for the next
+// attempt, every time a request is about to be retried. Observers are called in the
+// order they were registered in, after the WithOnRetry callback if any. The returned
+// function deregisters fn.
+func (c *Client) OnRetry(fn RetryObserver) CancelFunc {
+	return c.retryBus.subscribe(fn)
+}
+
+func (b *retryBus) subscribe(fn RetryObserver) CancelFunc {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.observers = append(b.observers, registeredRetryObserver{id: id, fn: fn})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for i, observer := range b.observers {
+			if observer.id == id {
+				b.observers = append(b.observers[:i:i], b.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *retryBus) publish(event RetryEvent) {
+	b.mu.RLock()
+	observers := b.observers
+	b.mu.RUnlock()
+
+	for _, observer := range observers {
+		observer.fn(event)
+	}
+}
@@ -0,0 +1,52 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnRetry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)))
+
+	var calls []string
+	var events []RetryEvent
+	cancelFirst := client.OnRetry(func(event RetryEvent) {
+		calls = append(calls, "first")
+		events = append(events, event)
+	})
+	client.OnRetry(func(event RetryEvent) {
+		calls = append(calls, "second")
+	})
+
+	_, err := client.List(context.Background())
+
+	assert.Error(t, err)
+	// the third and last attempt is not followed by a retry, thus not reported
+	assert.Equal(t, []string{"first", "second", "first", "second"}, calls)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, 2, events[1].Attempt)
+		assert.Equal(t, http.MethodGet, events[1].Method)
+		assert.Equal(t, ts.URL+"/v1/organisation/accounts", events[1].URL)
+		assert.Equal(t, http.StatusServiceUnavailable, events[1].StatusCode)
+		assert.True(t, events[1].Elapsed >= events[0].Elapsed)
+	}
+
+	cancelFirst()
+	cancelFirst()
+	calls = nil
+
+	_, err = client.List(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, []string{"second", "second"}, calls)
+}
@@ -0,0 +1,110 @@
+package form3
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fieldSchemas holds the constraints of the fields of an organisation account, keyed by
+// their JSON name, that cannot be derived from their Go type. They follow the Form3
+// documentation.
+var fieldSchemas = map[string]map[string]interface{}{
+	"type":                     {"enum": []string{"accounts"}},
+	"version":                  {"minimum": 0},
+	"country":                  {"format": "iso-3166-1-alpha-2", "pattern": "^[A-Z]{2}$"},
+	"base_currency":            {"format": "iso-4217", "pattern": "^[A-Z]{3}$"},
+	"account_number":           {"maxLength": 64},
+	"bank_id":                  {"maxLength": 16},
+	"bank_id_code":             {"maxLength": 16},
+	"bic":                      {"pattern": "^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$"},
+	"iban":                     {"pattern": "^[A-Z]{2}[0-9]{2}[A-Z0-9]{0,64}$"},
+	"name":                     {"minItems": 1, "maxItems": maxNames, "items": map[string]interface{}{"type": "string", "maxLength": maxNameLength}},
+	"alternative_names":        {"maxItems": 3, "items": map[string]interface{}{"type": "string", "maxLength": maxNameLength}},
+	"account_classification":   {"enum": []AccountClassification{ClassificationPersonal, ClassificationBusiness}},
+	"secondary_identification": {"maxLength": maxNameLength},
+}
+
+// requiredFields holds the JSON names of the fields of each struct that the Form3 API
+// requires, keyed by the struct type.
+var requiredFields = map[reflect.Type][]string{
+	reflect.TypeOf(OrganisationAccount{}):           {"id", "type", "organisation_id", "attributes"},
+	reflect.TypeOf(OrganisationAccountAttributes{}): {"country", "name"},
+}
+
+// OrganisationAccountSchema returns the JSON Schema (draft-07) of an organisation
+// account, e.g. to generate request validators or documentation. It is generated
+// from the JSON tags of OrganisationAccount and its attributes, thus follows any
+// change made to them.
+func OrganisationAccountSchema() []byte {
+	schema := schemaFor(reflect.TypeOf(OrganisationAccount{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "OrganisationAccount"
+
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		// the schema is only made of maps, slices and basic types
+		panic(err)
+	}
+
+	return b
+}
+
+// schemaFor returns the JSON Schema of the given type.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(uuid.UUID{}) {
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	}
+
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+
+			property := schemaFor(field.Type)
+			for key, value := range fieldSchemas[name] {
+				property[key] = value
+			}
+			properties[name] = property
+		}
+
+		schema := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if required, ok := requiredFields[t]; ok {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{}
+	}
+}
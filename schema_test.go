@@ -0,0 +1,47 @@
+package form3
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganisationAccountSchema(t *testing.T) {
+	var schema struct {
+		Schema     string   `json:"$schema"`
+		Required   []string `json:"required"`
+		Properties struct {
+			ID         map[string]interface{} `json:"id"`
+			Attributes struct {
+				Required   []string                          `json:"required"`
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"attributes"`
+		} `json:"properties"`
+	}
+
+	err := json.Unmarshal(OrganisationAccountSchema(), &schema)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema.Schema)
+	assert.Equal(t, []string{"id", "type", "organisation_id", "attributes"}, schema.Required)
+	assert.Equal(t, "uuid", schema.Properties.ID["format"])
+
+	attributes := schema.Properties.Attributes
+	assert.Equal(t, []string{"country", "name"}, attributes.Required)
+	assert.Equal(t, "iso-3166-1-alpha-2", attributes.Properties["country"]["format"])
+	assert.Equal(t, "iso-4217", attributes.Properties["base_currency"]["format"])
+	assert.Equal(t, "array", attributes.Properties["name"]["type"])
+	assert.Equal(t, float64(maxNames), attributes.Properties["name"]["maxItems"])
+	assert.Equal(t, "boolean", attributes.Properties["joint_account"]["type"])
+
+	// every attribute has a property in the schema
+	attributesType := reflect.TypeOf(OrganisationAccountAttributes{})
+	assert.Len(t, attributes.Properties, attributesType.NumField())
+	for i := 0; i < attributesType.NumField(); i++ {
+		name := strings.Split(attributesType.Field(i).Tag.Get("json"), ",")[0]
+		assert.Contains(t, attributes.Properties, name)
+	}
+}
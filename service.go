@@ -2,13 +2,20 @@ package form3
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -32,36 +39,368 @@ var (
 	// max elapsed time for the rate limiter to retry requests
 	// in production would be much bigger
 	backoffMaxElapsedTime = 10 * time.Second
+
+	// default version of the Form3 API the client talks to
+	defaultAPIVersion = "v1"
+
+	// default number of bytes of a request body that the client buffers to be able to retry the request
+	defaultMaxBufferedBodySize int64 = 10 << 20
+
+	// default number of bytes of a response body that the client copies for the response observer
+	defaultMaxResponseBodyBytes int64 = 10 << 20
+
+	// number of bytes of an error response body that the client reads to get the error message
+	maxErrorBodySize int64 = 64 << 10
+
+	// default number of IDs passed to FilterByIDs that are sent in a single List request
+	defaultMaxFilterIDs = 100
+
+	// default envelope of the Form3 API, where resources are wrapped inside "data"
+	defaultEnvelope = EnvelopeConfig{
+		DataKey:     "data",
+		ListDataKey: "data",
+	}
 )
 
+// EnvelopeConfig describes the JSON object that wraps resources sent to and
+// received from the API, e.g. {"data": {...}}.
+type EnvelopeConfig struct {
+	// DataKey is the key wrapping a single organisation account.
+	DataKey string
+	// ListDataKey is the key wrapping a list of organisation accounts.
+	ListDataKey string
+}
+
+// AccountService is implemented by types able to create, fetch, list and delete
+// organisation accounts, such as Client and the wrappers around it.
+type AccountService interface {
+	Create(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (OrganisationAccount, error)
+	Fetch(ctx context.Context, accountID uuid.UUID, roo ...RequestOption) (OrganisationAccount, error)
+	List(ctx context.Context, loo ...ListOption) ([]OrganisationAccount, error)
+	Delete(ctx context.Context, accountID uuid.UUID, version int) error
+}
+
+var _ AccountService = (*Client)(nil)
+
 // Client is the service that interacts with the Form3 API. It can perform
 // the following actions on Organisation Accounts: create, fetch, list and delete.
 type Client struct {
-	baseURL    string
-	httpClient http.Client
+	baseURL         string
+	httpClient      *httpClientSlot
+	transport       *http.Transport
+	dialer          *net.Dialer
+	roundTripper    http.RoundTripper
+	newBackOff      func() backoff.BackOff
+	customBackOff   bool
+	onRetry         func(attempt int, resp *http.Response, err error)
+	maxFilterIDs    int
+	defaultPageSize int
+	paginationDelay time.Duration
+	stats           *clientStats
+	logger          Logger
+	preCreateHooks  []func(*OrganisationAccount) error
+	postFetchHooks  []func(*OrganisationAccount) error
+	envelope        EnvelopeConfig
+	apiVersion      string
+	apiKey          string
+	maxRetries      int
+	maxBodyBuffer   int64
+	clockFunc       func() time.Time
+	pingEndpoint    string
+	traceHook       TraceHook
+	stale           *staleCache
+	versionCheck    *versionCheck
+	retryBus        *retryBus
+	encryptor       *fieldEncryptor
+	correlationID   func(ctx context.Context) string
+	decoder         ResponseDecoder
+	contentType     string
+	accept          string
+	fieldAliases    FieldAliases
+	strictDecoding  bool
+	listGuard       int
+	maxListResults  int
+	retryBudget     *RetryBudget
+	tenantHeader    string
+	auditLogger     AuditLogger
+	actorExtractor  func(ctx context.Context) string
+	rateSchedule    rateSchedule
+	conflictMerge   func(existing, incoming OrganisationAccount) OrganisationAccount
+
+	retryOnNetworkError bool
+	lifecycle           *lifecycle
+	meshHeaders         http.Header
+
+	// configErr is set by client options given invalid arguments, and returned by
+	// every request since NewClient cannot return it
+	configErr error
+
+	responseObserver     func(method, url string, statusCode int, body []byte)
+	maxResponseBodyBytes int64
+
+	backoffInitialInterval time.Duration
+	backoffMultiplier      float64
+	backoffMaxInterval     time.Duration
+	backoffJitter          float64
 }
 
 // NewClient returns a new instance of the client service that
-// interacts with the Form3 API.
-func NewClient(baseURL string) *Client {
-	return &Client{
+// interacts with the Form3 API. Its default configuration can be
+// changed by passing any number of client options.
+func NewClient(baseURL string, coo ...ClientOption) *Client {
+	c := &Client{
 		baseURL: baseURL,
-		httpClient: http.Client{
-			Timeout: timeout,
+		httpClient: &httpClientSlot{
+			client: http.Client{Timeout: timeout},
 		},
+		transport:     http.DefaultTransport.(*http.Transport).Clone(),
+		maxFilterIDs:  defaultMaxFilterIDs,
+		stats:         &clientStats{},
+		envelope:      defaultEnvelope,
+		apiVersion:    defaultAPIVersion,
+		maxRetries:    -1,
+		maxBodyBuffer: defaultMaxBufferedBodySize,
+		clockFunc:     time.Now,
+		retryBus:      &retryBus{},
+		backoffJitter: backoff.DefaultRandomizationFactor,
+		decoder:       JSONDecoder{},
+		tenantHeader:  defaultTenantHeader,
+		accept:        defaultAccept,
+		lifecycle:     newLifecycle(),
+
+		maxResponseBodyBytes: defaultMaxResponseBodyBytes,
+	}
+	c.newBackOff = c.exponentialBackOff
+
+	for _, co := range coo {
+		co(c)
 	}
+
+	c.httpClient.client.Transport = c.transport
+	if c.roundTripper != nil {
+		c.httpClient.client.Transport = c.roundTripper
+	}
+
+	return c
+}
+
+// Reset returns a new client with the same configuration as c, but none of the state
+// c accumulated while performing requests: its statistics are zeroed, its stale cache
+// is empty, the API version is checked again, and the observers registered with OnRetry
+// are not carried over. c is left unchanged. Both clients share the HTTP transport, and
+// the retry budget if any.
+func (c *Client) Reset() *Client {
+	reset := *c
+
+	reset.httpClient = &httpClientSlot{client: *c.httpClient.load()}
+	reset.stats = &clientStats{}
+	reset.retryBus = &retryBus{}
+	if c.stale != nil {
+		reset.stale = newStaleCache(c.stale.ttl)
+	}
+	if c.versionCheck != nil {
+		reset.versionCheck = &versionCheck{}
+	}
+	if c.rateSchedule != nil {
+		reset.rateSchedule = c.rateSchedule.refilled()
+	}
+	reset.lifecycle = newLifecycle()
+
+	// the default back-off is bound to the client it was created for
+	if !c.customBackOff {
+		reset.newBackOff = reset.exponentialBackOff
+	}
+
+	return &reset
+}
+
+// SetHTTPClient replaces the HTTP client used to perform requests, e.g. to
+// enable tracing at runtime. It is safe to call while requests are in flight:
+// they complete with the HTTP client they started with.
+func (c *Client) SetHTTPClient(client http.Client) {
+	c.httpClient.update(func(hc *http.Client) {
+		*hc = client
+	})
+}
+
+// SetTransport keeps the current HTTP client, but makes it perform requests
+// using the given transport. Like SetHTTPClient, it is safe to call while
+// requests are in flight.
+func (c *Client) SetTransport(rt http.RoundTripper) {
+	c.httpClient.update(func(hc *http.Client) {
+		hc.Transport = rt
+	})
+}
+
+// httpClientSlot holds the HTTP client of a Client, which SetHTTPClient and
+// SetTransport may replace while requests are in flight.
+type httpClientSlot struct {
+	mu     sync.RWMutex
+	client http.Client
+}
+
+// load returns a copy of the current HTTP client, to perform a request with.
+func (s *httpClientSlot) load() *http.Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client := s.client
+	return &client
+}
+
+// update changes the current HTTP client with fn.
+func (s *httpClientSlot) update(fn func(*http.Client)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fn(&s.client)
+}
+
+// WarmUp issues n concurrent HEAD requests to the base URL to fill the connection
+// pool, so that latency-sensitive requests made right after startup do not pay for
+// establishing connections. Note that the transport only keeps MaxIdleConnsPerHost
+// idle connections. WarmUp succeeds as long as at least one connection could be
+// established, and logs how many were through the configured logger. It does nothing
+// if n is not positive, and it is cancelled by CancelInflight like any other request.
+func (c *Client) WarmUp(ctx context.Context, n int) error {
+	if c.lifecycle.isClosed() {
+		return ErrClientClosed
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	ctx, cancel := c.lifecycle.bind(ctx)
+	defer cancel()
+
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			c.setHeaders(ctx, req, nil)
+
+			resp, err := c.httpClient.load().Do(req)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer resp.Body.Close()
+
+			_, err = io.Copy(io.Discard, resp.Body)
+			errs <- err
+		}()
+	}
+
+	var established int
+	var lastErr error
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			lastErr = err
+			continue
+		}
+		established++
+	}
+
+	c.logf("warm up established %d out of %d connections", established, n)
+
+	if established == 0 {
+		if c.lifecycle.isClosed() {
+			return ErrClientClosed
+		}
+		return fmt.Errorf("could not warm up any connection: %w", lastErr)
+	}
+
+	return nil
+}
+
+// Ping sends a single HEAD request to the health endpoint of the API and returns how
+// long it took to get the response, e.g. to continuously sample the latency of the API.
+// It neither retries the request nor reads the response body. A response status other
+// than 2xx is returned as an *APIError, along with the measured latency.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	if c.lifecycle.isClosed() {
+		return 0, ErrClientClosed
+	}
+
+	endpoint := c.pingEndpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("/%s/health", c.apiVersion)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL+endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	c.setHeaders(ctx, req, nil)
+
+	start := time.Now()
+
+	resp, err := c.httpClient.load().Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	elapsed := time.Since(start)
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return elapsed, &APIError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+
+	return elapsed, nil
 }
 
 // Fetch returns an organisation account given its accountID in the form of
-// an UUID V4.
-func (c *Client) Fetch(accountID uuid.UUID) (OrganisationAccount, error) {
+// an UUID V4. Of the request options, only IncludeRelatedFetch applies to it.
+func (c *Client) Fetch(ctx context.Context, accountID uuid.UUID, roo ...RequestOption) (_ OrganisationAccount, err error) {
+	defer func() {
+		c.audit(ctx, AuditOperationFetch, accountID, err)
+	}()
+
+	var options requestOptions
+	for _, ro := range roo {
+		ro(&options)
+	}
+
+	if options.err != nil {
+		return OrganisationAccount{}, options.err
+	}
+
+	var query string
+	if len(options.include) != 0 {
+		query = "?" + url.Values{"include": {strings.Join(options.include, ",")}}.Encode()
+	}
+
+	url := fmt.Sprintf(
+		"%s/%s/organisation/accounts/%s%s",
+		c.baseURL,
+		c.apiVersion,
+		accountID.String(),
+		query,
+	)
+
+	organisationAccount, err := c.fetch(ctx, url)
+	if c.stale != nil {
+		value, err := c.stale.resolve(url, organisationAccount, err)
+		return value.(OrganisationAccount), err
+	}
+
+	return organisationAccount, err
+}
+
+// fetch performs the request to fetch the organisation account found at the given URL.
+func (c *Client) fetch(ctx context.Context, url string) (OrganisationAccount, error) {
 	resp, err := c.performRequest(
+		ctx,
 		http.MethodGet,
-		fmt.Sprintf(
-			"%s/v1/organisation/accounts/%s",
-			c.baseURL,
-			accountID.String(),
-		),
+		url,
+		nil,
 		nil,
 	)
 	if err != nil {
@@ -74,108 +413,534 @@ func (c *Client) Fetch(accountID uuid.UUID) (OrganisationAccount, error) {
 		return OrganisationAccount{}, err
 	}
 
-	var organisationAccount struct {
-		Data OrganisationAccount `json:"data"`
+	var (
+		organisationAccount OrganisationAccount
+		included            []json.RawMessage
+	)
+	err = c.decodeEnvelopeFields(
+		resp.Body,
+		envelopeField{key: c.envelope.DataKey, v: &organisationAccount},
+		envelopeField{key: "included", v: &included, metadata: true},
+	)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	err = attachIncluded(&organisationAccount, included)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	err = c.receive(&organisationAccount)
+	if err != nil {
+		return OrganisationAccount{}, err
 	}
-	err = json.NewDecoder(resp.Body).Decode(&organisationAccount)
+
+	err = c.runHooks(c.postFetchHooks, &organisationAccount)
 	if err != nil {
 		return OrganisationAccount{}, err
 	}
 
-	return organisationAccount.Data, nil
+	return organisationAccount, nil
+}
+
+// AccountExists reports whether an organisation account with the given ID exists.
+// It issues a HEAD request so that the account itself is not transferred, falling
+// back to Fetch if the API does not support HEAD requests.
+func (c *Client) AccountExists(ctx context.Context, accountID uuid.UUID) (bool, error) {
+	resp, err := c.performRequest(
+		ctx,
+		http.MethodHead,
+		fmt.Sprintf(
+			"%s/%s/organisation/accounts/%s",
+			c.baseURL,
+			c.apiVersion,
+			accountID.String(),
+		),
+		nil,
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		_, err = c.Fetch(ctx, accountID)
+	} else {
+		err = c.checkErrorMessage(resp)
+	}
+
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 // List returns a list of organisation accounts. It can support paging,
 // which implies that the caller of the method should provide a page
 // number and its size.
-func (c *Client) List(loo ...ListOption) ([]OrganisationAccount, error) {
+func (c *Client) List(ctx context.Context, loo ...ListOption) ([]OrganisationAccount, error) {
 	options := listOptions{}
 	for _, lo := range loo {
 		lo(&options)
 	}
 
-	url, err := url.Parse(fmt.Sprintf("%s/v1/organisation/accounts", c.baseURL))
+	if options.err != nil {
+		return nil, options.err
+	}
+
+	if len(options.ids) == 0 {
+		organisationAccounts, err := c.list(ctx, options)
+		if err == nil && c.listGuard > 0 && !options.filtered() && len(organisationAccounts) > c.listGuard {
+			return nil, fmt.Errorf("%w: %d accounts listed without any filter, more than %d", ErrListGuardExceeded, len(organisationAccounts), c.listGuard)
+		}
+		return organisationAccounts, err
+	}
+
+	ids := options.ids
+
+	var organisationAccounts []OrganisationAccount
+	for remaining := ids; len(remaining) > 0; {
+		batchSize := c.maxFilterIDs
+		if batchSize <= 0 || batchSize > len(remaining) {
+			batchSize = len(remaining)
+		}
+
+		options.ids = remaining[:batchSize]
+		remaining = remaining[batchSize:]
+
+		batch, err := c.list(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+
+		organisationAccounts = append(organisationAccounts, batch...)
+	}
+
+	sortByIDs(organisationAccounts, ids)
+
+	return organisationAccounts, nil
+}
+
+// list performs a single List request against the Form3 API.
+func (c *Client) list(ctx context.Context, options listOptions) ([]OrganisationAccount, error) {
+	url, err := c.listURL(options)
 	if err != nil {
 		return nil, err
 	}
 
+	page, err := c.listPage(ctx, url)
+	organisationAccounts := page.Accounts
+	if c.stale != nil {
+		value, err := c.stale.resolve(url, organisationAccounts, err)
+		return value.([]OrganisationAccount), err
+	}
+
+	return organisationAccounts, err
+}
+
+// listURL returns the URL listing the organisation accounts selected by the list options.
+func (c *Client) listURL(options listOptions) (string, error) {
+	url, err := url.Parse(fmt.Sprintf("%s/%s/organisation/accounts", c.baseURL, c.apiVersion))
+	if err != nil {
+		return "", err
+	}
+
 	urlQuery := url.Query()
 
-	if options.pageNumber != 0 {
+	if options.pageNumberSet {
 		urlQuery.Set("page[number]", strconv.Itoa(options.pageNumber))
 	}
 
-	if options.pageSize != 0 {
+	if options.pageToken != "" {
+		urlQuery.Set("page[token]", options.pageToken)
+	}
+
+	if options.pageSizeSet {
 		urlQuery.Set("page[size]", strconv.Itoa(options.pageSize))
+	} else if c.defaultPageSize != 0 {
+		urlQuery.Set("page[size]", strconv.Itoa(c.defaultPageSize))
+	}
+
+	if len(options.ids) != 0 {
+		ids := make([]string, 0, len(options.ids))
+		for _, id := range options.ids {
+			ids = append(ids, id.String())
+		}
+		urlQuery.Set("filter[id][in]", strings.Join(ids, ","))
+	}
+
+	if options.organisationID != uuid.Nil {
+		urlQuery.Set("filter[organisation_id]", options.organisationID.String())
+	}
+
+	if options.accountClassification != "" {
+		urlQuery.Set("filter[account_classification]", string(options.accountClassification))
+	}
+
+	if options.createdBy != "" {
+		urlQuery.Set("filter[created_by]", options.createdBy)
+	}
+
+	if options.includeDeleted {
+		urlQuery.Set("filter[include_deleted]", "true")
+	}
+
+	if options.onlyDeleted {
+		urlQuery.Set("filter[only_deleted]", "true")
+	}
+
+	if len(options.fields) != 0 {
+		urlQuery.Set("fields[accounts]", strings.Join(options.fields, ","))
+	}
+
+	if len(options.include) != 0 {
+		urlQuery.Set("include", strings.Join(options.include, ","))
+	}
+
+	if !options.modifiedAfter.IsZero() {
+		urlQuery.Set("filter[modified_on][gt]", options.modifiedAfter.UTC().Format(time.RFC3339))
+	}
+
+	if !options.createdAfter.IsZero() {
+		urlQuery.Set("filter[created_on][gt]", options.createdAfter.UTC().Format(time.RFC3339))
 	}
 
 	url.RawQuery = urlQuery.Encode()
 
+	return url.String(), nil
+}
+
+// listPage performs the request to list the organisation accounts found at the given URL.
+func (c *Client) listPage(ctx context.Context, url string) (ListResult, error) {
 	resp, err := c.performRequest(
+		ctx,
 		http.MethodGet,
-		url.String(),
+		url,
+		nil,
 		nil,
 	)
 	if err != nil {
-		return nil, err
+		return ListResult{}, err
 	}
 	defer resp.Body.Close()
 
 	err = c.checkErrorMessage(resp)
 	if err != nil {
-		return nil, err
+		return ListResult{}, err
+	}
+
+	var (
+		organisationAccounts []OrganisationAccount
+		links                struct {
+			Next string `json:"next"`
+		}
+		meta struct {
+			TotalCount *int `json:"total_count"`
+		}
+		included []json.RawMessage
+	)
+	err = c.decodeEnvelopeFields(
+		resp.Body,
+		envelopeField{key: c.envelope.ListDataKey, v: &organisationAccounts},
+		envelopeField{key: "links", v: &links, metadata: true},
+		envelopeField{key: "meta", v: &meta, metadata: true},
+		envelopeField{key: "included", v: &included, metadata: true},
+	)
+	if err != nil {
+		return ListResult{}, err
+	}
+
+	for i := range organisationAccounts {
+		err = attachIncluded(&organisationAccounts[i], included)
+		if err != nil {
+			return ListResult{}, err
+		}
+
+		err = c.receive(&organisationAccounts[i])
+		if err != nil {
+			return ListResult{}, err
+		}
+
+		err = c.runHooks(c.postFetchHooks, &organisationAccounts[i])
+		if err != nil {
+			return ListResult{}, err
+		}
 	}
 
-	var organisationAccounts struct {
-		Data []OrganisationAccount `json:"data"`
+	totalCount := -1
+	if meta.TotalCount != nil {
+		totalCount = *meta.TotalCount
 	}
-	err = json.NewDecoder(resp.Body).Decode(&organisationAccounts)
+
+	return ListResult{
+		Accounts:      organisationAccounts,
+		NextPageToken: pageToken(links.Next),
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// pageToken returns the page token found in the query of the given link, if any.
+func pageToken(link string) string {
+	u, err := url.Parse(link)
 	if err != nil {
-		return nil, err
+		return ""
+	}
+
+	return u.Query().Get("page[token]")
+}
+
+// sortByIDs orders the organisation accounts by the position of their ID inside ids.
+func sortByIDs(organisationAccounts []OrganisationAccount, ids []uuid.UUID) {
+	positions := make(map[uuid.UUID]int, len(ids))
+	for i, id := range ids {
+		if _, ok := positions[id]; !ok {
+			positions[id] = i
+		}
+	}
+
+	position := func(id uuid.UUID) int {
+		if p, ok := positions[id]; ok {
+			return p
+		}
+		return len(ids)
 	}
 
-	return organisationAccounts.Data, nil
+	sort.SliceStable(organisationAccounts, func(i, j int) bool {
+		return position(organisationAccounts[i].ID) < position(organisationAccounts[j].ID)
+	})
 }
 
 // Delete will remove an organisation account given its account ID and version.
-func (c *Client) Delete(accountID uuid.UUID, version int) error {
+func (c *Client) Delete(ctx context.Context, accountID uuid.UUID, version int) (err error) {
+	defer func() {
+		c.audit(ctx, AuditOperationDelete, accountID, err)
+	}()
+
 	resp, err := c.performRequest(
+		ctx,
 		http.MethodDelete,
 		fmt.Sprintf(
-			"%s/v1/organisation/accounts/%s?version=%d",
+			"%s/%s/organisation/accounts/%s?version=%d",
 			c.baseURL,
+			c.apiVersion,
 			accountID.String(),
 			version,
 		),
 		nil,
+		nil,
 	)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	return c.checkErrorMessage(resp)
+	err = c.checkErrorMessage(resp)
+	if err == nil {
+		c.forgetStale(accountID)
+	}
+
+	return err
+}
+
+// forgetStale drops the fetched copies of the organisation account kept to be served
+// when the API is unavailable, if any, once the account changed.
+func (c *Client) forgetStale(accountID uuid.UUID) {
+	if c.stale != nil {
+		c.stale.forget(fmt.Sprintf("%s/%s/organisation/accounts/%s", c.baseURL, c.apiVersion, accountID.String()))
+	}
+}
+
+// DeleteIfExists removes an organisation account just like Delete, but it
+// treats an account that does not exist as already deleted. It is meant for
+// cleanup code that only cares about the account being gone.
+func (c *Client) DeleteIfExists(ctx context.Context, accountID uuid.UUID, version int) error {
+	err := c.Delete(ctx, accountID, version)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
 }
 
 // Create will create a new organisation account.
-func (c *Client) Create(organisationAccount OrganisationAccount) (OrganisationAccount, error) {
-	body := struct {
-		Data OrganisationAccount `json:"data"`
-	}{
-		Data: organisationAccount,
+func (c *Client) Create(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (OrganisationAccount, error) {
+	var options requestOptions
+	for _, ro := range roo {
+		ro(&options)
+	}
+
+	if options.err != nil {
+		c.audit(ctx, AuditOperationCreate, organisationAccount.ID, options.err)
+		return OrganisationAccount{}, options.err
+	}
+
+	created, err := c.create(ctx, organisationAccount, nil, options)
+
+	// a conflict resolved by merging is still a failed create, while the fetch and
+	// the update resolving it are audited on their own
+	c.audit(ctx, AuditOperationCreate, organisationAccount.ID, err)
+
+	if errors.Is(err, ErrConflict) && c.conflictMerge != nil {
+		return c.mergeOnConflict(ctx, organisationAccount, err, roo)
+	}
+
+	return created, err
+}
+
+// mergeOnConflict fetches the organisation account that the given one conflicts with,
+// merges them with the function set by WithConflictMergeStrategy and updates the
+// existing account with the result. It is only attempted once: an error updating the
+// account, even a conflict, is returned as is. An error fetching the existing account,
+// e.g. because the conflict is on another unique attribute than the ID, is returned
+// wrapped in conflictErr, the error of the create.
+func (c *Client) mergeOnConflict(ctx context.Context, incoming OrganisationAccount, conflictErr error, roo []RequestOption) (OrganisationAccount, error) {
+	existing, err := c.Fetch(ctx, incoming.ID)
+	if err != nil {
+		return OrganisationAccount{}, fmt.Errorf("%w: could not fetch account %s to merge with: %v", conflictErr, incoming.ID, err)
+	}
+
+	merged := c.conflictMerge(existing, incoming)
+	merged.ID = existing.ID
+	merged.Version = existing.Version
+
+	return c.Update(ctx, merged, roo...)
+}
+
+// CreateIfAbsent creates a new organisation account, unless one with the same ID
+// already exists. It asks the API to enforce this with an If-None-Match: * header,
+// so that concurrent callers cannot create duplicates. The returned bool is true if
+// the account was created and false if it already existed, in which case the
+// existing account is fetched and returned.
+func (c *Client) CreateIfAbsent(ctx context.Context, organisationAccount OrganisationAccount) (OrganisationAccount, bool, error) {
+	header := http.Header{}
+	header.Set("If-None-Match", "*")
+
+	created, err := c.create(ctx, organisationAccount, header, requestOptions{})
+	c.audit(ctx, AuditOperationCreate, organisationAccount.ID, err)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed {
+		existing, err := c.Fetch(ctx, organisationAccount.ID)
+		if err != nil {
+			return OrganisationAccount{}, false, err
+		}
+
+		return existing, false, nil
+	}
+	if err != nil {
+		return OrganisationAccount{}, false, err
+	}
+
+	return created, true, nil
+}
+
+// create performs the request to create a new organisation account, adding the
+// given headers to it.
+func (c *Client) create(ctx context.Context, organisationAccount OrganisationAccount, header http.Header, options requestOptions) (OrganisationAccount, error) {
+	err := c.runHooks(c.preCreateHooks, &organisationAccount)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	return c.send(
+		ctx,
+		http.MethodPost,
+		fmt.Sprintf("%s/%s/organisation/accounts", c.baseURL, c.apiVersion),
+		organisationAccount,
+		header,
+		options,
+	)
+}
+
+// Update changes an existing organisation account, identified by its ID, to match the
+// given one. The version of the given account must be the current one. To only change
+// some attributes, pass IncludeFields or ExcludeFields.
+func (c *Client) Update(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (_ OrganisationAccount, err error) {
+	defer func() {
+		c.audit(ctx, AuditOperationUpdate, organisationAccount.ID, err)
+	}()
+
+	var options requestOptions
+	for _, ro := range roo {
+		ro(&options)
+	}
+
+	if options.err != nil {
+		return OrganisationAccount{}, options.err
+	}
+
+	updated, err := c.send(
+		ctx,
+		http.MethodPatch,
+		fmt.Sprintf(
+			"%s/%s/organisation/accounts/%s",
+			c.baseURL,
+			c.apiVersion,
+			organisationAccount.ID.String(),
+		),
+		organisationAccount,
+		nil,
+		options,
+	)
+	if err == nil {
+		c.forgetStale(organisationAccount.ID)
+	}
+
+	return updated, err
+}
+
+// send performs a request carrying the given organisation account, such as the ones
+// creating and updating it, and returns the organisation account the API responds with.
+func (c *Client) send(ctx context.Context, method string, url string, organisationAccount OrganisationAccount, header http.Header, options requestOptions) (OrganisationAccount, error) {
+	if c.configErr != nil {
+		return OrganisationAccount{}, c.configErr
+	}
+
+	toSend := organisationAccount
+	toSend.Included = nil
+	if c.encryptor != nil {
+		err := c.encryptor.encrypt(&toSend)
+		if err != nil {
+			return OrganisationAccount{}, err
+		}
+	}
+
+	data, err := marshalAccount(toSend, options)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	body := map[string]json.RawMessage{
+		c.envelope.DataKey: data,
 	}
 
 	bodyBytes := new(bytes.Buffer)
 
-	err := json.NewEncoder(bodyBytes).Encode(&body)
+	err = json.NewEncoder(bodyBytes).Encode(&body)
 	if err != nil {
 		return OrganisationAccount{}, err
 	}
 
+	if len(options.prefer) > 0 {
+		header = header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Prefer", strings.Join(options.prefer, ", "))
+	}
+
 	resp, err := c.performRequest(
-		http.MethodPost,
-		fmt.Sprintf("%s/v1/organisation/accounts", c.baseURL),
+		ctx,
+		method,
+		url,
 		bodyBytes,
+		header,
 	)
 	if err != nil {
 		return OrganisationAccount{}, err
@@ -187,72 +952,467 @@ func (c *Client) Create(organisationAccount OrganisationAccount) (OrganisationAc
 		return OrganisationAccount{}, err
 	}
 
-	var data struct {
-		Data OrganisationAccount `json:"data"`
+	// a server honouring Prefer may not send the resource back
+	if resp.StatusCode == http.StatusNoContent {
+		return organisationAccount, nil
+	}
+
+	var sent OrganisationAccount
+	err = c.decodeEnvelope(resp.Body, c.envelope.DataKey, &sent)
+	if errors.Is(err, io.EOF) && resp.StatusCode == http.StatusAccepted {
+		return organisationAccount, nil
+	}
+	if err != nil {
+		return OrganisationAccount{}, err
 	}
-	err = json.NewDecoder(resp.Body).Decode(&data)
+
+	err = c.receive(&sent)
 	if err != nil {
 		return OrganisationAccount{}, err
 	}
 
-	return data.Data, nil
+	return sent, nil
 }
 
 // performRequest is the general method called by all exported methods of the client library
 // to perform a request against the Form3 API.
 //
-// It uses an exponential back-off algorithm so that it can retry certain operations given
-// a certain set of status codes (situated inside retriableStatusCodes at the top).
-func (c *Client) performRequest(method string, url string, body io.Reader) (*http.Response, error) {
-	expBackOff := backoff.NewExponentialBackOff()
-	expBackOff.MaxElapsedTime = backoffMaxElapsedTime
-	ticker := backoff.NewTicker(expBackOff)
+// It uses a back-off algorithm (exponential by default) so that it can retry certain operations
+// given a certain set of status codes (situated inside retriableStatusCodes at the top). Retrying
+// stops as soon as ctx is cancelled. If the API still responds with one of these status codes
+// when the back-off gives up, a *RetryError is returned, as it is when the connection keeps failing
+// with an error retried by WithRetryOnNetworkError. The given header, which may be nil, is added to every attempt.
+// Since every attempt sends the body from its start, a body that cannot be rewound is buffered
+// in memory first.
+//
+// The request is cancelled by CancelInflight until the body of its response is closed.
+func (c *Client) performRequest(ctx context.Context, method string, url string, body io.Reader, header http.Header) (*http.Response, error) {
+	if c.lifecycle.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	ctx, cancel := c.lifecycle.bind(ctx)
+
+	resp, err := c.performAttempts(ctx, method, url, body, header)
+	if err != nil {
+		cancel()
+		if c.lifecycle.isClosed() {
+			return nil, ErrClientClosed
+		}
+		return resp, err
+	}
+
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// performAttempts performs the attempts of a request on behalf of performRequest.
+func (c *Client) performAttempts(ctx context.Context, method string, url string, body io.Reader, header http.Header) (*http.Response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+
+	err := c.checkVersionOnce(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.rateSchedule != nil {
+		err = c.rateSchedule.wait(ctx, c.clockFunc())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	replayableBody, err := c.replayable(body)
+	if err != nil {
+		return nil, err
+	}
+
+	b := c.newBackOff()
+	switch {
+	case c.maxRetries == 0:
+		// backoff.WithMaxRetries treats 0 as no limit
+		b = &backoff.StopBackOff{}
+	case c.maxRetries > 0:
+		b = backoff.WithMaxRetries(b, uint64(c.maxRetries))
+	}
+	b = backoff.WithContext(b, ctx)
+	b.Reset()
 
 	var req *http.Request
 	var resp *http.Response
-	var err error
+	var attempt int
+
+	start := time.Now()
+	defer func() {
+		c.stats.recordRequest(attempt, resp, err, time.Since(start))
+	}()
+
+	for {
+		attempt++
+
+		// the previous response is only kept around in case the back-off gives up,
+		// so release its connection before trying again
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		var reqBody io.Reader
+		if replayableBody != nil {
+			_, err = replayableBody.Seek(0, io.SeekStart)
+			if err != nil {
+				break
+			}
+			reqBody = replayableBody
+		}
 
-	for range ticker.C {
-		req, err = http.NewRequest(
+		req, err = http.NewRequestWithContext(
+			ctx,
 			method,
 			url,
-			body,
+			reqBody,
 		)
 		if err != nil {
-			ticker.Stop()
 			break
 		}
 
-		resp, err = c.httpClient.Do(req)
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = &countingReadCloser{ReadCloser: req.Body, count: &c.stats.totalRequestBodyBytes}
+		}
+
+		if c.contentType != "" && reqBody != nil {
+			req.Header.Set("Content-Type", c.contentType)
+		}
+		c.setHeaders(ctx, req, header)
+
+		resp, err = c.do(req)
+		c.stats.recordAttempt(req, resp)
 		if err != nil {
-			ticker.Stop()
+			if c.retryOnNetworkError && (isEOF(err) || isConnectionReset(err)) &&
+				c.awaitRetry(ctx, b, attempt, method, url, nil, err, start) {
+				continue
+			}
+
 			break
 		}
+		resp.Body = &countingReadCloser{ReadCloser: resp.Body, count: &c.stats.totalResponseBodyBytes}
 
 		if _, ok := retriableStatusCodes[resp.StatusCode]; ok {
+			if !c.awaitRetry(ctx, b, attempt, method, url, resp, nil, start) {
+				break
+			}
 			continue
 		}
 
-		ticker.Stop()
 		break
 	}
 
+	// the back-off gave up while the connection kept failing with a retriable error
+	if err != nil && c.retryOnNetworkError && (isEOF(err) || isConnectionReset(err)) {
+		return nil, &RetryError{
+			Err:          err,
+			Attempts:     attempt,
+			TotalElapsed: time.Since(start),
+		}
+	}
+
+	// the back-off gave up while the API kept responding with a retriable status
+	if err == nil && resp != nil {
+		if _, ok := retriableStatusCodes[resp.StatusCode]; ok {
+			err = &RetryError{
+				Err:          c.checkErrorMessage(resp),
+				Attempts:     attempt,
+				TotalElapsed: time.Since(start),
+			}
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	return resp, err
+}
+
+// setHeaders sets the headers sent with every request to the API: the API key, the
+// Accept header, the tenant and correlation IDs found in ctx and the service mesh
+// headers, followed by the given header, which may be nil.
+func (c *Client) setHeaders(ctx context.Context, req *http.Request, header http.Header) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	if c.accept != "" {
+		req.Header.Set("Accept", c.accept)
+	}
+
+	if tenantID := tenantFromContext(ctx); tenantID != "" && c.tenantHeader != "" {
+		req.Header.Set(c.tenantHeader, tenantID)
+	}
+
+	if c.correlationID != nil {
+		if id := c.correlationID(ctx); id != "" {
+			req.Header.Set(correlationIDHeader, id)
+		}
+	}
+
+	for key, values := range c.meshHeaders {
+		req.Header[key] = values
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+}
+
+// awaitRetry reports whether the given failed attempt of a request is retried, which
+// the back-off b and the retry budget, if any, may deny. If it is, awaitRetry notifies
+// the retry observers and waits for as long as b says before returning. The budget and
+// the observers are only involved once b grants another attempt, thus a request giving
+// up neither spends a token nor reports a retry that never happens.
+func (c *Client) awaitRetry(ctx context.Context, b backoff.BackOff, attempt int, method string, url string, resp *http.Response, err error, start time.Time) bool {
+	wait := b.NextBackOff()
+	if wait == backoff.Stop {
+		return false
+	}
+
+	// a retry that does not fit in the budget is not attempted at all
+	if c.retryBudget != nil && !c.retryBudget.allow() {
+		return false
+	}
+
+	if c.onRetry != nil {
+		c.onRetry(attempt, resp, err)
+	}
+
+	event := RetryEvent{
+		Attempt: attempt,
+		Method:  method,
+		URL:     url,
+		Err:     err,
+		Elapsed: time.Since(start),
+	}
+	if resp != nil {
+		event.StatusCode = resp.StatusCode
+	}
+	c.retryBus.publish(event)
+
+	return sleepContext(ctx, wait) == nil
+}
+
+// do sends a single request, letting the trace hook and the response observer, if
+// any, observe it.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var traceCtx context.Context
+	if c.traceHook != nil {
+		traceCtx = c.traceHook.BeforeRequest(req)
+	}
+
+	rec := debugRecorderFromContext(req.Context())
+	var exchange int
+	if rec != nil {
+		exchange = rec.start(req)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.load().Do(req)
+
+	if rec != nil {
+		resp, err = rec.finish(exchange, req, resp, err)
+	}
+
+	if c.traceHook != nil {
+		c.traceHook.AfterRequest(traceCtx, resp, err, time.Since(start))
+	}
+
+	if err == nil && c.responseObserver != nil {
+		body, err := peekBody(resp, c.maxResponseBodyBytes)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		c.responseObserver(req.Method, req.URL.String(), resp.StatusCode, body)
+	}
+
 	return resp, err
 }
 
+// replayable returns the given body as an io.ReadSeeker, so that it can be sent again
+// when retrying a request. Bodies that are not seekable already are read into memory,
+// unless they are larger than the client accepts to buffer.
+func (c *Client) replayable(body io.Reader) (io.ReadSeeker, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	if rs, ok := body.(io.ReadSeeker); ok {
+		return rs, nil
+	}
+
+	buf, err := ioutil.ReadAll(io.LimitReader(body, c.maxBodyBuffer+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(buf)) > c.maxBodyBuffer {
+		return nil, fmt.Errorf("%w: body is larger than %d bytes", ErrBodyTooLargeToBuffer, c.maxBodyBuffer)
+	}
+
+	return bytes.NewReader(buf), nil
+}
+
+// receive prepares an organisation account received from the API to be handed to the
+// caller, migrating it to the API version of the client and decrypting its encrypted
+// fields, if any.
+func (c *Client) receive(organisationAccount *OrganisationAccount) error {
+	err := c.migrate(organisationAccount)
+	if err != nil {
+		return err
+	}
+
+	if c.encryptor != nil {
+		return c.encryptor.decrypt(organisationAccount)
+	}
+
+	return nil
+}
+
+// decodeEnvelope decodes the object read from r with the decoder of the client and stores
+// the value found under key inside v, a non-nil pointer. If the object has no such key,
+// v is left untouched.
+func (c *Client) decodeEnvelope(r io.Reader, key string, v interface{}) error {
+	return c.decodeEnvelopeFields(r, envelopeField{key: key, v: v})
+}
+
+// envelopeField is a value to decode from the given key of an envelope.
+type envelopeField struct {
+	key string
+	v   interface{}
+
+	// metadata fields, such as links, are decoded as they are, without aliases
+	// nor strict decoding
+	metadata bool
+}
+
+// decodeEnvelopeFields decodes the object read from r with the decoder of the client and
+// stores the values found under the keys of the fields inside them, just like
+// decodeEnvelope does for a single key.
+func (c *Client) decodeEnvelopeFields(r io.Reader, fields ...envelopeField) error {
+	// a struct with a pointer field tagged with each key, so that any decoder honouring
+	// json struct tags can decode the envelope, and a missing key is told apart from
+	// a zero value
+	structFields := make([]reflect.StructField, len(fields))
+	for i, field := range fields {
+		// with aliases or strict decoding, the data is decoded generically first
+		fieldType := reflect.TypeOf(field.v).Elem()
+		if c.decodesGenerically(field) {
+			fieldType = reflect.TypeOf((*interface{})(nil)).Elem()
+		}
+
+		structFields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: reflect.PtrTo(fieldType),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:%q`, field.key)),
+		}
+	}
+	envelope := reflect.New(reflect.StructOf(structFields))
+
+	err := c.decoder.Decode(r, envelope.Interface())
+	if err != nil {
+		return err
+	}
+
+	for i, field := range fields {
+		data := envelope.Elem().Field(i)
+		if data.IsNil() {
+			continue
+		}
+
+		if c.decodesGenerically(field) {
+			err = c.decodeGeneric(data.Elem().Interface(), field.v)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		reflect.ValueOf(field.v).Elem().Set(data.Elem())
+	}
+
+	return nil
+}
+
+// decodesGenerically returns whether the envelope field must first be decoded into
+// a generic value, to be passed to decodeGeneric.
+func (c *Client) decodesGenerically(field envelopeField) bool {
+	return !field.metadata && (len(c.fieldAliases) > 0 || c.strictDecoding)
+}
+
+// decodeGeneric stores the generically decoded value data into v, a non-nil pointer,
+// once the aliases of the client are applied, failing on unknown fields if the
+// client decodes strictly.
+func (c *Client) decodeGeneric(data interface{}, v interface{}) error {
+	b, err := json.Marshal(c.fieldAliases.rename(data))
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	if c.strictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+
+	return decoder.Decode(v)
+}
+
+// runHooks calls the hooks in order on the organisation account, stopping at the first error.
+func (c *Client) runHooks(hooks []func(*OrganisationAccount) error, organisationAccount *OrganisationAccount) error {
+	for _, hook := range hooks {
+		if err := hook(organisationAccount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logf logs a message through the configured logger, if any.
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
+
 // checkErrorMessage verifies if we made a bad request, in which case
-// we parse the error message and return it to the caller
+// we parse the error message and return it to the caller as an *APIError.
+// Bodies that are not JSON, such as the plain text sent by proxies, are kept
+// as they are as the error message.
 func (c *Client) checkErrorMessage(resp *http.Response) error {
 	if resp.StatusCode >= 300 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+
+		// the status is what matters, thus failing to read the message is not an error
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+		if len(bytes.TrimSpace(body)) == 0 {
+			return apiErr
+		}
+
 		var data struct {
 			ErrorMessage string `json:"error_message"`
 		}
-		err := json.NewDecoder(resp.Body).Decode(&data)
-		if err != nil {
-			return err
+		if json.Unmarshal(body, &data) != nil {
+			apiErr.ErrorMessage = strings.TrimSpace(string(body))
+			return apiErr
 		}
 
-		return errors.New(data.ErrorMessage)
+		apiErr.ErrorMessage = data.ErrorMessage
+		return apiErr
 	}
 
 	return nil
@@ -1,356 +1,1412 @@
 package form3
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/cenkalti/backoff"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/suite"
 )
 
-// Form3TestSuite defines the integration testing that we want
-// to perform against the fake accounts API inside the container.
-type Form3TestSuite struct {
-	suite.Suite
-	client            *Client
-	testOrganisations []OrganisationAccount
+func TestRateLimiter(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectErr   bool
+		handlerFunc func() http.HandlerFunc
+	}{
+		{
+			name:      "OK - rate limiter retries till service is available",
+			expectErr: false,
+			handlerFunc: func() http.HandlerFunc {
+				var serviceUnavailableCounter int
+
+				return func(w http.ResponseWriter, r *http.Request) {
+					if serviceUnavailableCounter == 3 {
+						data := struct {
+							Data []struct{} `json:"data"`
+						}{
+							Data: []struct{}{},
+						}
+
+						w.WriteHeader(http.StatusOK)
+
+						_ = json.NewEncoder(w).Encode(&data)
+
+						return
+					}
+
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					message := struct {
+						ErrorMessage string `json:"error_message"`
+					}{
+						ErrorMessage: "Service Unavailable",
+					}
+
+					_ = json.NewEncoder(w).Encode(&message)
+
+					serviceUnavailableCounter++
+				}
+			},
+		},
+		{
+			name:      "Not OK - rate limiter sees 429 on every call, returns error",
+			expectErr: true,
+			handlerFunc: func() http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					message := struct {
+						ErrorMessage string `json:"error_message"`
+					}{
+						ErrorMessage: "Service Unavailable",
+					}
+
+					_ = json.NewEncoder(w).Encode(&message)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle("/v1/organisation/accounts", tc.handlerFunc())
+
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			// wait as little as possible between attempts, while a clock moving a second
+			// forward on every reading makes the back-off give up after a few of them
+			client := NewClient(ts.URL, WithBackoffInitialInterval(time.Millisecond))
+			client.clockFunc = newFastForwardClock(time.Second)
+
+			_, err := client.List(context.Background())
+
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
-// This method is run before each test and it will
-// create all the organisations insidde valid_organisations JSON file.
-func (s *Form3TestSuite) SetupTest() {
-	s.client = NewClient(os.Getenv("API_BASE_URL"))
+// newFastForwardClock returns a clock that starts at the current time and moves
+// forward by step every time it is read.
+func newFastForwardClock(step time.Duration) func() time.Time {
+	var mu sync.Mutex
+	now := time.Now()
 
-	f, err := os.Open(os.Getenv("TESTDATA_ORGANISATIONS_FILE_PATH"))
-	if err != nil {
-		panic(err)
+	return func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now = now.Add(step)
+		return now
 	}
+}
 
-	var organisations []OrganisationAccount
-	err = json.NewDecoder(f).Decode(&organisations)
-	if err != nil {
-		panic(err)
+func TestDeleteIfExists(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		expectErr  bool
+	}{
+		{
+			name:       "OK - organisation removed",
+			statusCode: http.StatusNoContent,
+			expectErr:  false,
+		},
+		{
+			name:       "OK - organisation does not exist",
+			statusCode: http.StatusNotFound,
+			expectErr:  false,
+		},
+		{
+			name:       "Conflict - incorrect version is still returned",
+			statusCode: http.StatusConflict,
+			expectErr:  true,
+		},
 	}
 
-	s.testOrganisations = organisations
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer ts.Close()
 
-	for _, org := range s.testOrganisations {
-		_, err := s.client.Create(org)
-		if err != nil {
-			panic(err)
+			client := NewClient(ts.URL)
+
+			err := client.DeleteIfExists(context.Background(), uuid.New(), 0)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithOnRetry(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	var attempts []int
+	client := NewClient(
+		ts.URL,
+		WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)),
+		WithOnRetry(func(attempt int, resp *http.Response, err error) {
+			attempts = append(attempts, attempt)
+			assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+			assert.NoError(t, err)
+		}),
+	)
+
+	_, err := client.List(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, attempts)
+}
+
+func TestBackoffDoesNotCarryOverRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	var attempts []int
+	client := NewClient(
+		ts.URL,
+		WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)),
+		WithOnRetry(func(attempt int, resp *http.Response, err error) {
+			attempts = append(attempts, attempt)
+		}),
+	)
+
+	// every request gets a back-off of its own, thus a client reused across test
+	// cases has no back-off state to reset between them
+	for i := 0; i < 2; i++ {
+		attempts = nil
+
+		_, err := client.List(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, []int{1, 2}, attempts)
+	}
+}
+
+func TestListFilterByIDs(t *testing.T) {
+	var requests int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		ids := strings.Split(r.URL.Query().Get("filter[id][in]"), ",")
+
+		// respond in reverse order so that the client has to restore the order of the IDs
+		var data struct {
+			Data []OrganisationAccount `json:"data"`
+		}
+		for i := len(ids) - 1; i >= 0; i-- {
+			data.Data = append(data.Data, OrganisationAccount{ID: uuid.MustParse(ids[i])})
+		}
+
+		_ = json.NewEncoder(w).Encode(&data)
+	}))
+	defer ts.Close()
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+	client := NewClient(ts.URL, WithMaxFilterIDs(2))
+
+	orgs, err := client.List(context.Background(), FilterByIDs(ids...))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Len(t, orgs, len(ids))
+	for i, org := range orgs {
+		assert.Equal(t, ids[i], org.ID)
+	}
+}
+
+// newBenchmarkClient returns a client that talks to a test server always responding
+// with the given status code and body, and that never retries.
+func newBenchmarkClient(b *testing.B, statusCode int, body string) *Client {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	b.Cleanup(ts.Close)
+
+	return NewClient(ts.URL, WithBackoffStrategy(func() backoff.BackOff {
+		return &backoff.StopBackOff{}
+	}))
+}
+
+const benchmarkAccountJSON = `{"id":"a9e3b971-a241-4930-a09f-a7c04bf394fe","type":"accounts",` +
+	`"organisation_id":"b6eca650-cf86-4715-b312-fcef0fec1506","version":0,` +
+	`"attributes":{"country":"GB","base_currency":"GBP","account_number":"41426819",` +
+	`"bank_id":"400300","bank_id_code":"GBDSC","bic":"NWBKGB22","iban":"GB11NWBK40030041426819",` +
+	`"name":["Jane Doe"],"account_classification":"Personal"}}`
+
+func BenchmarkCreate(b *testing.B) {
+	client := newBenchmarkClient(b, http.StatusCreated, `{"data":`+benchmarkAccountJSON+`}`)
+
+	var account OrganisationAccount
+	if err := json.Unmarshal([]byte(benchmarkAccountJSON), &account); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Create(context.Background(), account); err != nil {
+			b.Fatal(err)
 		}
 	}
 }
 
-// This method is run after each test  and it will
-// clear up all organisations that were created.
-func (s *Form3TestSuite) TearDownTest() {
-	orgs, err := s.client.List()
-	if err != nil {
-		panic(err)
+func BenchmarkFetch(b *testing.B) {
+	client := newBenchmarkClient(b, http.StatusOK, `{"data":`+benchmarkAccountJSON+`}`)
+	id := uuid.MustParse("a9e3b971-a241-4930-a09f-a7c04bf394fe")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Fetch(context.Background(), id); err != nil {
+			b.Fatal(err)
+		}
 	}
+}
 
-	for _, org := range orgs {
-		err := s.client.Delete(org.ID, org.Version)
-		if err != nil {
-			panic(err)
+func BenchmarkList(b *testing.B) {
+	accounts := strings.Repeat(benchmarkAccountJSON+",", 99) + benchmarkAccountJSON
+	client := newBenchmarkClient(b, http.StatusOK, `{"data":[`+accounts+`]}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.List(context.Background()); err != nil {
+			b.Fatal(err)
 		}
 	}
 }
 
-func (s *Form3TestSuite) TestFetch() {
+func BenchmarkDelete(b *testing.B) {
+	client := newBenchmarkClient(b, http.StatusNoContent, "")
+	id := uuid.MustParse("a9e3b971-a241-4930-a09f-a7c04bf394fe")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := client.Delete(context.Background(), id, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAccountExists(t *testing.T) {
 	testCases := []struct {
-		name               string
-		id                 uuid.UUID
-		shouldReturnErr    bool
-		expectedErrMessage string
+		name           string
+		headStatusCode int
+		getStatusCode  int
+		expectedExists bool
+		expectErr      bool
 	}{
 		{
-			name: "OK - existing org",
-			id:   uuid.MustParse("a9e3b971-a241-4930-a09f-a7c04bf394fe"),
+			name:           "OK - existing org",
+			headStatusCode: http.StatusOK,
+			expectedExists: true,
+		},
+		{
+			name:           "OK - non-existing org",
+			headStatusCode: http.StatusNotFound,
+			expectedExists: false,
 		},
 		{
-			name:               "Not Found - non-existing org",
-			id:                 uuid.MustParse("3cbbba27-3b51-42f4-88a7-729fa42a4a68"),
-			shouldReturnErr:    true,
-			expectedErrMessage: "does not exist",
+			name:           "OK - HEAD not supported, falls back to fetching an existing org",
+			headStatusCode: http.StatusMethodNotAllowed,
+			getStatusCode:  http.StatusOK,
+			expectedExists: true,
+		},
+		{
+			name:           "OK - HEAD not supported, falls back to fetching a non-existing org",
+			headStatusCode: http.StatusMethodNotAllowed,
+			getStatusCode:  http.StatusNotFound,
+			expectedExists: false,
+		},
+		{
+			name:           "Not OK - unexpected error",
+			headStatusCode: http.StatusForbidden,
+			expectErr:      true,
 		},
 	}
 
 	for _, tc := range testCases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			org, err := s.client.Fetch(tc.id)
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					w.WriteHeader(tc.headStatusCode)
+					return
+				}
+
+				w.WriteHeader(tc.getStatusCode)
+				_, _ = w.Write([]byte(`{"data":{}}`))
+			}))
+			defer ts.Close()
 
-			if tc.shouldReturnErr {
+			client := NewClient(ts.URL)
+
+			exists, err := client.AccountExists(context.Background(), uuid.New())
+
+			if tc.expectErr {
 				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tc.expectedErrMessage)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tc.id, org.ID)
 			}
+			assert.Equal(t, tc.expectedExists, exists)
 		})
 	}
 }
 
-func (s *Form3TestSuite) TestList() {
+func TestCreateIfAbsent(t *testing.T) {
+	existing := OrganisationAccount{ID: uuid.New(), Type: "accounts", Version: 3}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data struct {
+			Data OrganisationAccount `json:"data"`
+		}
+
+		if r.Method == http.MethodGet {
+			data.Data = existing
+			_ = json.NewEncoder(w).Encode(&data)
+			return
+		}
+
+		assert.Equal(t, "*", r.Header.Get("If-None-Match"))
+
+		_ = json.NewDecoder(r.Body).Decode(&data)
+		if data.Data.ID == existing.ID {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&data)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
 	testCases := []struct {
-		name         string
-		paging       bool
-		pageNumber   int
-		pageSize     int
-		expectedOrgs []OrganisationAccount
+		name            string
+		orgToCreate     OrganisationAccount
+		expectedOrg     OrganisationAccount
+		expectedCreated bool
 	}{
 		{
-			name:         "OK - all organisations",
-			expectedOrgs: s.testOrganisations,
+			name:            "OK - organisation created",
+			orgToCreate:     OrganisationAccount{ID: uuid.New(), Type: "accounts"},
+			expectedCreated: true,
 		},
 		{
-			name:         "OK - first 2 organisations",
-			paging:       true,
-			pageNumber:   0,
-			pageSize:     2,
-			expectedOrgs: s.testOrganisations[:2],
+			name:            "OK - organisation already exists",
+			orgToCreate:     OrganisationAccount{ID: existing.ID, Type: "accounts"},
+			expectedOrg:     existing,
+			expectedCreated: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			org, created, err := client.CreateIfAbsent(context.Background(), tc.orgToCreate)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCreated, created)
+			if tc.expectedCreated {
+				assert.Equal(t, tc.orgToCreate, org)
+			} else {
+				assert.Equal(t, tc.expectedOrg, org)
+			}
+		})
+	}
+}
+
+func TestWithConflictMergeStrategy(t *testing.T) {
+	existing := OrganisationAccount{
+		ID:         uuid.New(),
+		Type:       "accounts",
+		Version:    3,
+		Attributes: OrganisationAccountAttributes{Country: "GB", BankID: "400300"},
+	}
+
+	var (
+		requests      []string
+		patchConflict bool
+		fetchMissing  bool
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+
+		var data struct {
+			Data OrganisationAccount `json:"data"`
+		}
+
+		switch {
+		case r.Method == http.MethodGet && fetchMissing:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodGet:
+			data.Data = existing
+		case r.Method == http.MethodPost, patchConflict:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_message":"conflict"}`))
+			return
+		default:
+			_ = json.NewDecoder(r.Body).Decode(&data)
+		}
+
+		_ = json.NewEncoder(w).Encode(&data)
+	}))
+	defer ts.Close()
+
+	// keeps the bank ID of the existing account, takes the rest from the incoming one
+	merge := func(existing, incoming OrganisationAccount) OrganisationAccount {
+		incoming.Attributes.BankID = existing.Attributes.BankID
+		return incoming
+	}
+
+	incoming := OrganisationAccount{
+		ID:         existing.ID,
+		Type:       "accounts",
+		Attributes: OrganisationAccountAttributes{Country: "FR", BankID: "123456"},
+	}
+
+	testCases := []struct {
+		name             string
+		options          []ClientOption
+		patchConflict    bool
+		fetchMissing     bool
+		expectedRequests []string
+		expectedOrg      OrganisationAccount
+		expectedErr      error
+		unexpectedErr    error
+	}{
+		{
+			name:             "OK - conflicting accounts are merged",
+			options:          []ClientOption{WithConflictMergeStrategy(merge)},
+			expectedRequests: []string{http.MethodPost, http.MethodGet, http.MethodPatch},
+			expectedOrg: OrganisationAccount{
+				ID:         existing.ID,
+				Type:       "accounts",
+				Version:    3,
+				Attributes: OrganisationAccountAttributes{Country: "FR", BankID: "400300"},
+			},
+		},
+		{
+			name:             "Not OK - merge is attempted once",
+			options:          []ClientOption{WithConflictMergeStrategy(merge)},
+			patchConflict:    true,
+			expectedRequests: []string{http.MethodPost, http.MethodGet, http.MethodPatch},
+			expectedErr:      ErrConflict,
 		},
 		{
-			name:         "OK - last 2 organisations",
-			paging:       true,
-			pageNumber:   1,
-			pageSize:     3,
-			expectedOrgs: s.testOrganisations[3:],
+			name:             "Not OK - conflict on another attribute than the ID",
+			options:          []ClientOption{WithConflictMergeStrategy(merge)},
+			fetchMissing:     true,
+			expectedRequests: []string{http.MethodPost, http.MethodGet},
+			expectedErr:      ErrConflict,
+			unexpectedErr:    ErrNotFound,
 		},
 		{
-			name:         "OK - no organisations",
-			paging:       true,
-			pageNumber:   5,
-			pageSize:     5,
-			expectedOrgs: nil,
+			name:             "Not OK - no merge strategy",
+			expectedRequests: []string{http.MethodPost},
+			expectedErr:      ErrConflict,
 		},
 	}
 
 	for _, tc := range testCases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			var options []ListOption
-			if tc.paging {
-				options = append(
-					options,
-					PageNumberListOption(tc.pageNumber),
-					PageSizeListOption(tc.pageSize),
-				)
+		t.Run(tc.name, func(t *testing.T) {
+			requests = nil
+			patchConflict = tc.patchConflict
+			fetchMissing = tc.fetchMissing
+
+			client := NewClient(ts.URL, tc.options...)
+
+			org, err := client.Create(context.Background(), incoming)
+
+			assert.Equal(t, tc.expectedRequests, requests)
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+				if tc.unexpectedErr != nil {
+					assert.False(t, errors.Is(err, tc.unexpectedErr))
+				}
+				return
 			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOrg, org)
+		})
+	}
 
-			orgs, err := s.client.List(options...)
+	t.Run("OK - merged create is audited as failed", func(t *testing.T) {
+		requests = nil
+		patchConflict = false
+		fetchMissing = false
+
+		var recorder auditRecorder
+		client := NewClient(ts.URL, WithConflictMergeStrategy(merge), WithAuditLogger(&recorder))
+
+		_, err := client.Create(context.Background(), incoming)
+		assert.NoError(t, err)
+
+		var operations []string
+		var successes []bool
+		for _, event := range recorder {
+			operations = append(operations, event.Operation)
+			successes = append(successes, event.Success)
+		}
+		assert.Equal(t, []string{AuditOperationCreate, AuditOperationFetch, AuditOperationUpdate}, operations)
+		assert.Equal(t, []bool{false, true, true}, successes)
+	})
+}
+
+func TestWithDefaultPageSize(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          []ListOption
+		expectedPageSize string
+	}{
+		{
+			name:             "OK - default page size is used",
+			expectedPageSize: "25",
+		},
+		{
+			name:             "OK - explicit page size overrides the default",
+			options:          []ListOption{PageSizeListOption(5)},
+			expectedPageSize: "5",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedPageSize, r.URL.Query().Get("page[size]"))
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}))
+			defer ts.Close()
+
+			client := NewClient(ts.URL, WithDefaultPageSize(25))
+
+			_, err := client.List(context.Background(), tc.options...)
 
 			assert.NoError(t, err)
-			assert.Equal(t, len(tc.expectedOrgs), len(orgs))
-			assert.ElementsMatch(t, tc.expectedOrgs, orgs)
 		})
 	}
 }
 
-func (s *Form3TestSuite) TestDelete() {
+func TestPageListOptions(t *testing.T) {
 	testCases := []struct {
-		name               string
-		id                 uuid.UUID
-		version            int
-		expectedErr        bool
-		expectedErrMessage string
-		expectedOrgs       []OrganisationAccount
+		name          string
+		options       []ListOption
+		expectedQuery map[string][]string
 	}{
 		{
-			name:         "OK - organisation removed",
-			id:           uuid.MustParse("a9e3b971-a241-4930-a09f-a7c04bf394fe"),
-			version:      0,
-			expectedErr:  false,
-			expectedOrgs: s.testOrganisations[1:],
+			name:          "OK - no paging options",
+			expectedQuery: map[string][]string{},
 		},
-		// the test below should actually return an error as per the official documentation
-		// of the API (404 Not Found), but in the fake API you guys implemented, it returns 204 if a resource
-		// you're trying to delete does not exist, so I adapted the test to run as per the
-		// fake API implementation
 		{
-			name:         "OK - organisation does not exist in Form3, but no error",
-			id:           uuid.MustParse("a0607d72-11d2-4a3a-87f8-186e53b07811"),
-			version:      0,
-			expectedErr:  false,
-			expectedOrgs: s.testOrganisations[1:],
+			name:          "OK - explicit first page",
+			options:       []ListOption{PageNumberListOption(0)},
+			expectedQuery: map[string][]string{"page[number]": {"0"}},
 		},
 		{
-			name:               "Conflict - incorrect version used when removing an existing organisation",
-			id:                 uuid.MustParse("3c76048a-2024-4917-b911-1b3e88fccfb3"),
-			version:            5,
-			expectedErr:        true,
-			expectedErrMessage: "invalid version",
+			name:          "OK - explicit first page and page size",
+			options:       []ListOption{PageNumberListOption(0), PageSizeListOption(10)},
+			expectedQuery: map[string][]string{"page[number]": {"0"}, "page[size]": {"10"}},
+		},
+		{
+			name:          "OK - explicit zero page size",
+			options:       []ListOption{PageSizeListOption(0)},
+			expectedQuery: map[string][]string{"page[size]": {"0"}},
 		},
 	}
 
 	for _, tc := range testCases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			err := s.client.Delete(tc.id, tc.version)
-
-			if tc.expectedErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tc.expectedErrMessage)
-			} else {
-				assert.NoError(t, err)
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.expectedQuery, map[string][]string(r.URL.Query()))
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}))
+			defer ts.Close()
 
-				orgs, err := s.client.List()
-
-				assert.NoError(t, err)
-				assert.Equal(t, len(tc.expectedOrgs), len(orgs))
-				assert.ElementsMatch(t, tc.expectedOrgs, orgs)
-			}
+			_, err := NewClient(ts.URL).List(context.Background(), tc.options...)
 
+			assert.NoError(t, err)
 		})
 	}
 }
 
-func (s *Form3TestSuite) TestCreate() {
-	f, err := os.Open(os.Getenv("TESTDATA_CREATE_ORGANISATIONS_FILE_PATH"))
-	if err != nil {
-		panic(err)
-	}
+type logRecorder []string
+
+func (l *logRecorder) Printf(format string, v ...interface{}) {
+	*l = append(*l, fmt.Sprintf(format, v...))
+}
+
+func TestWarmUp(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+	}))
+	defer ts.Close()
+
+	t.Run("OK - connections established", func(t *testing.T) {
+		var logs logRecorder
+		client := NewClient(ts.URL, WithLogger(&logs))
+
+		err := client.WarmUp(context.Background(), 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, logRecorder{"warm up established 3 out of 3 connections"}, logs)
+	})
+
+	t.Run("Not OK - no connection established", func(t *testing.T) {
+		client := NewClient("http://127.0.0.1:0")
+
+		err := client.WarmUp(context.Background(), 2)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("OK - no connection requested", func(t *testing.T) {
+		client := NewClient(ts.URL)
+
+		assert.NoError(t, client.WarmUp(context.Background(), 0))
+		assert.NoError(t, client.WarmUp(context.Background(), -1))
+	})
+
+	t.Run("Not OK - cancelled by CancelInflight", func(t *testing.T) {
+		started := make(chan struct{}, 2)
+		blocking := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-r.Context().Done()
+		}))
+		defer blocking.Close()
+
+		client := NewClient(blocking.URL)
+		go func() {
+			<-started
+			client.CancelInflight()
+		}()
+
+		err := client.WarmUp(context.Background(), 2)
 
-	var createOrgs []OrganisationAccount
+		assert.True(t, errors.Is(err, ErrClientClosed))
+	})
+}
+
+func TestHooks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// echo the created organisation back, or return a lowercase one when fetching
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.Copy(w, r.Body)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data":{"attributes":{"country":"gb"}}}`))
+	}))
+	defer ts.Close()
 
-	err = json.NewDecoder(f).Decode(&createOrgs)
-	if err != nil {
-		panic(err)
+	upperCountry := func(org *OrganisationAccount) error {
+		org.Attributes.Country = Country(strings.ToUpper(string(org.Attributes.Country)))
+		return nil
+	}
+	trimIBAN := func(org *OrganisationAccount) error {
+		if org.Attributes.IBAN != nil {
+			org.Attributes.IBAN = String(strings.ReplaceAll(*org.Attributes.IBAN, " ", ""))
+		}
+		return nil
 	}
 
+	client := NewClient(
+		ts.URL,
+		WithPreCreateHook(upperCountry),
+		WithPreCreateHook(trimIBAN),
+		WithPostFetchHook(upperCountry),
+	)
+
+	created, err := client.Create(context.Background(), OrganisationAccount{
+		Attributes: OrganisationAccountAttributes{
+			Country: "gb",
+			IBAN:    String("GB29 NWBK 6016 1331 9268 19"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, CountryGB, created.Attributes.Country)
+	assert.Equal(t, "GB29NWBK60161331926819", *created.Attributes.IBAN)
+
+	fetched, err := client.Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, CountryGB, fetched.Attributes.Country)
+
+	errHook := errors.New("hook failed")
+	client = NewClient(ts.URL, WithPreCreateHook(func(*OrganisationAccount) error { return errHook }))
+
+	_, err = client.Create(context.Background(), OrganisationAccount{})
+	assert.Equal(t, errHook, err)
+}
+
+func TestListFilterByOrganisationID(t *testing.T) {
+	organisationID := uuid.New()
+
 	testCases := []struct {
-		name               string
-		orgToCreate        OrganisationAccount
-		expectedErr        bool
-		expectedErrMessage string
-		expectedOrgs       []OrganisationAccount
+		name          string
+		options       []ListOption
+		expectedQuery string
+		expectedErr   error
 	}{
 		{
-			name:         "OK - organisation created",
-			orgToCreate:  createOrgs[0],
-			expectedErr:  false,
-			expectedOrgs: append(s.testOrganisations, createOrgs[0]),
+			name:          "OK - single organisation",
+			options:       []ListOption{FilterByOrganisationID(organisationID)},
+			expectedQuery: organisationID.String(),
 		},
 		{
-			name:               "Bad Request - organisation already exists",
-			orgToCreate:        createOrgs[1],
-			expectedErr:        true,
-			expectedErrMessage: "violates a duplicate constraint",
+			name:          "OK - same organisation twice",
+			options:       []ListOption{FilterByOrganisationID(organisationID), FilterByOrganisationID(organisationID)},
+			expectedQuery: organisationID.String(),
 		},
 		{
-			name:               "Bad Request - organisation has invalid fields",
-			orgToCreate:        createOrgs[2],
-			expectedErr:        true,
-			expectedErrMessage: "validation failure list",
+			name:        "Not OK - conflicting organisations",
+			options:     []ListOption{FilterByOrganisationID(organisationID), FilterByOrganisationID(uuid.New())},
+			expectedErr: ErrConflictingListOptions,
 		},
 	}
 
 	for _, tc := range testCases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			org, err := s.client.Create(tc.orgToCreate)
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				assert.Equal(t, tc.expectedQuery, r.URL.Query().Get("filter[organisation_id]"))
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}))
+			defer ts.Close()
 
-			if tc.expectedErr {
-				assert.Error(t, err)
-				assert.Contains(t, err.Error(), tc.expectedErrMessage)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tc.orgToCreate, org)
+			client := NewClient(ts.URL)
 
-				orgs, err := s.client.List()
+			_, err := client.List(context.Background(), tc.options...)
 
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+				assert.Equal(t, 0, requests)
+			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, len(tc.expectedOrgs), len(orgs))
-				assert.ElementsMatch(t, tc.expectedOrgs, orgs)
+				assert.Equal(t, 1, requests)
 			}
-
 		})
 	}
 }
 
-func (s *Form3TestSuite) TestRateLimiter() {
+func TestWithEnvelope(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			var body map[string]OrganisationAccount
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Contains(t, body, "account")
+
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&body)
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"accounts":[{"type":"accounts"},{"type":"accounts"}]}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithEnvelope(EnvelopeConfig{DataKey: "account", ListDataKey: "accounts"}))
+
+	org := OrganisationAccount{ID: uuid.New(), Type: "accounts"}
+	created, err := client.Create(context.Background(), org)
+	assert.NoError(t, err)
+	assert.Equal(t, org, created)
+
+	orgs, err := client.List(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, orgs, 2)
+}
+
+func TestWithMaxRetries(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithMaxRetries(2), WithBackoffStrategy(ConstantBackoff(time.Millisecond)))
+
+	_, err := client.Fetch(context.Background(), uuid.New())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	// 0 disables retries
+	atomic.StoreInt32(&attempts, 0)
+	client = NewClient(ts.URL, WithMaxRetries(0), WithBackoffStrategy(ConstantBackoff(time.Millisecond)))
+
+	_, err = client.Fetch(context.Background(), uuid.New())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestServiceMeshRetry(t *testing.T) {
+	var (
+		attempts int32
+		headers  http.Header
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := NewClient(
+		ts.URL,
+		WithServiceMeshRetryHeaders(map[string]string{
+			"x-envoy-retry-on":    "5xx,reset",
+			"x-envoy-max-retries": "3",
+		}),
+		WithDisableClientRetry(),
+	)
+
+	_, _, err := client.CreateIfAbsent(context.Background(), newTestAccounts(1)[0])
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	assert.Equal(t, "5xx,reset", headers.Get("X-Envoy-Retry-On"))
+	assert.Equal(t, "3", headers.Get("X-Envoy-Max-Retries"))
+	assert.Equal(t, "*", headers.Get("If-None-Match"))
+}
+
+func TestRetryReplaysBody(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithBackoffStrategy(ConstantBackoff(time.Millisecond)))
+
+	_, err := client.Create(context.Background(), OrganisationAccount{ID: uuid.New()})
+
+	assert.NoError(t, err)
+	if assert.Len(t, bodies, 3) {
+		assert.NotEmpty(t, bodies[0])
+		assert.Equal(t, bodies[0], bodies[1])
+		assert.Equal(t, bodies[0], bodies[2])
+	}
+}
+
+func TestBodyTooLargeToBuffer(t *testing.T) {
+	client := NewClient("http://localhost", WithMaxBufferedBodySize(4))
+
+	// a strings.Reader can be rewound, thus is never buffered
+	_, err := client.replayable(strings.NewReader("0123456789"))
+	assert.NoError(t, err)
+
+	_, err = client.replayable(io.LimitReader(strings.NewReader("0123"), 4))
+	assert.NoError(t, err)
+
+	_, err = client.performRequest(context.Background(), http.MethodPost, "http://localhost", io.LimitReader(strings.NewReader("0123456789"), 10), nil)
+	assert.True(t, errors.Is(err, ErrBodyTooLargeToBuffer))
+}
+
+func TestListFilterByTime(t *testing.T) {
+	// a time outside of UTC is sent in UTC
+	since := time.Date(2021, 3, 1, 12, 30, 0, 0, time.FixedZone("EET", 2*60*60))
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2021-03-01T10:30:00Z", r.URL.Query().Get("filter[modified_on][gt]"))
+		assert.Equal(t, "2021-03-01T10:30:00Z", r.URL.Query().Get("filter[created_on][gt]"))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	_, err := client.List(context.Background(), FilterByModifiedAfter(since), FilterByCreatedAfter(since))
+
+	assert.NoError(t, err)
+}
+
+func TestPing(t *testing.T) {
 	testCases := []struct {
-		name        string
-		expectErr   bool
-		handlerFunc func() http.HandlerFunc
+		name         string
+		coo          []ClientOption
+		expectedPath string
+		statusCode   int
+		expectedErr  error
 	}{
 		{
-			name:      "OK - rate limiter retries till service is available",
-			expectErr: false,
-			handlerFunc: func() http.HandlerFunc {
-				var serviceUnavailableCounter int
+			name:         "OK - default endpoint",
+			expectedPath: "/v1/health",
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:         "OK - custom endpoint",
+			coo:          []ClientOption{WithPingEndpoint("/healthz")},
+			expectedPath: "/healthz",
+			statusCode:   http.StatusNoContent,
+		},
+		{
+			name:         "Not OK - unhealthy",
+			expectedPath: "/v1/health",
+			statusCode:   http.StatusServiceUnavailable,
+			expectedErr:  &APIError{StatusCode: http.StatusServiceUnavailable},
+		},
+	}
 
-				return func(w http.ResponseWriter, r *http.Request) {
-					if serviceUnavailableCounter == 3 {
-						data := struct {
-							Data []struct{} `json:"data"`
-						}{
-							Data: []struct{}{},
-						}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				assert.Equal(t, http.MethodHead, r.Method)
+				assert.Equal(t, tc.expectedPath, r.URL.Path)
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer ts.Close()
 
-						w.WriteHeader(http.StatusOK)
+			client := NewClient(ts.URL, append([]ClientOption{WithAPIKey("secret")}, tc.coo...)...)
 
-						_ = json.NewEncoder(w).Encode(&data)
+			latency, err := client.Ping(context.Background())
 
-						return
-					}
+			assert.Greater(t, int64(latency), int64(0))
+			assert.Equal(t, 1, requests)
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
 
-					w.WriteHeader(http.StatusTooManyRequests)
+func TestListFilterByAccountClassification(t *testing.T) {
+	organisationID := uuid.New()
 
-					message := struct {
-						ErrorMessage string `json:"error_message"`
-					}{
-						ErrorMessage: "Service Unavailable",
-					}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "Business", query.Get("filter[account_classification]"))
+		assert.Equal(t, organisationID.String(), query.Get("filter[organisation_id]"))
+		assert.Equal(t, "10", query.Get("page[size]"))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
 
-					_ = json.NewEncoder(w).Encode(&message)
+	client := NewClient(ts.URL)
 
-					serviceUnavailableCounter++
-				}
+	_, err := client.List(
+		context.Background(),
+		FilterByAccountClassification(ClassificationBusiness),
+		FilterByOrganisationID(organisationID),
+		PageSizeListOption(10),
+	)
+
+	assert.NoError(t, err)
+}
+
+func TestListFilterByCreatedBy(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "jane.doe@example.com", r.URL.Query().Get("filter[created_by]"))
+		_, _ = w.Write([]byte(`{"data":[{"created_by":"jane.doe@example.com","modified_by":"john.doe@example.com"}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	organisationAccounts, err := client.List(context.Background(), FilterByCreatedBy("jane.doe@example.com"))
+
+	assert.NoError(t, err)
+	if assert.Len(t, organisationAccounts, 1) {
+		assert.Equal(t, "jane.doe@example.com", organisationAccounts[0].CreatedBy)
+		assert.Equal(t, "john.doe@example.com", organisationAccounts[0].ModifiedBy)
+	}
+}
+
+func TestRetryError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error_message":"slow down"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)))
+
+	_, err := client.Fetch(context.Background(), uuid.New())
+
+	var retryErr *RetryError
+	if assert.True(t, errors.As(err, &retryErr)) {
+		assert.Equal(t, 3, retryErr.Attempts)
+		assert.True(t, retryErr.TotalElapsed > 0)
+	}
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.Equal(t, "slow down", apiErr.ErrorMessage)
+	}
+}
+
+func TestPlainTextErrorResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Proxy", "edge")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("404 page not found\n"))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	_, err := client.Fetch(context.Background(), uuid.New())
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	var apiErr *APIError
+	if assert.True(t, errors.As(err, &apiErr)) {
+		assert.Equal(t, "404 page not found", apiErr.ErrorMessage)
+		assert.Equal(t, "edge", apiErr.Header.Get("X-Proxy"))
+	}
+
+	assert.NoError(t, client.DeleteIfExists(context.Background(), uuid.New(), 0))
+}
+
+func TestWithRetryOnNetworkError(t *testing.T) {
+	// closeConn closes the connection of the request without responding, resetting
+	// it if reset is true
+	closeConn := func(w http.ResponseWriter, reset bool) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok && reset {
+			_ = tcpConn.SetLinger(0)
+		}
+		_ = conn.Close()
+	}
+
+	testCases := []struct {
+		name             string
+		reset            bool
+		failures         int
+		options          []ClientOption
+		expectedAttempts int
+		expectedErr      func(error) bool
+	}{
+		{
+			name:             "OK - EOF is retried",
+			failures:         2,
+			options:          []ClientOption{WithRetryOnNetworkError()},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "OK - connection reset is retried",
+			reset:            true,
+			failures:         2,
+			options:          []ClientOption{WithRetryOnNetworkError()},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "Not OK - network errors are not retried by default",
+			failures:         2,
+			expectedAttempts: 1,
+			expectedErr: func(err error) bool {
+				var retryErr *RetryError
+				return isEOF(err) && !errors.As(err, &retryErr)
 			},
 		},
 		{
-			name:      "Not OK - rate limiter sees 429 on every call, returns error",
-			expectErr: true,
-			handlerFunc: func() http.HandlerFunc {
-				return func(w http.ResponseWriter, r *http.Request) {
-					w.WriteHeader(http.StatusTooManyRequests)
-
-					message := struct {
-						ErrorMessage string `json:"error_message"`
-					}{
-						ErrorMessage: "Service Unavailable",
-					}
-
-					_ = json.NewEncoder(w).Encode(&message)
-				}
+			name:             "Not OK - back-off gives up",
+			reset:            true,
+			failures:         5,
+			options:          []ClientOption{WithRetryOnNetworkError()},
+			expectedAttempts: 3,
+			expectedErr: func(err error) bool {
+				var retryErr *RetryError
+				return isConnectionReset(err) && errors.As(err, &retryErr) && retryErr.Attempts == 3
 			},
 		},
 	}
 
 	for _, tc := range testCases {
-		s.T().Run(tc.name, func(t *testing.T) {
-			mux := http.NewServeMux()
-			mux.Handle("/v1/organisation/accounts", tc.handlerFunc())
-
-			ts := httptest.NewServer(mux)
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&attempts, 1)) <= tc.failures {
+					closeConn(w, tc.reset)
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}))
 			defer ts.Close()
 
-			client := NewClient(ts.URL)
+			var retried []error
+			options := append([]ClientOption{
+				WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 2)),
+				WithOnRetry(func(_ int, _ *http.Response, err error) {
+					retried = append(retried, err)
+				}),
+			}, tc.options...)
+			client := NewClient(ts.URL, options...)
 
-			_, err := client.List()
+			_, err := client.List(context.Background())
 
-			if tc.expectErr {
+			assert.Equal(t, int32(tc.expectedAttempts), atomic.LoadInt32(&attempts))
+			if tc.expectedErr != nil {
+				assert.True(t, tc.expectedErr(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, retried, tc.failures)
+			for _, err := range retried {
 				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
 			}
 		})
 	}
 }
 
-func TestForm3TestSuite(t *testing.T) {
-	suite.Run(t, new(Form3TestSuite))
+func TestListDeleted(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("filter[include_deleted]"))
+		_, _ = w.Write([]byte(`{"data":[{"created_on":"2021-03-01T10:30:00Z","deleted_on":"2021-03-02T10:30:00Z"},{}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	organisationAccounts, err := client.List(context.Background(), IncludeDeleted())
+
+	assert.NoError(t, err)
+	if assert.Len(t, organisationAccounts, 2) {
+		assert.Equal(t, time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC), *organisationAccounts[0].CreatedOn)
+		assert.Equal(t, time.Date(2021, 3, 2, 10, 30, 0, 0, time.UTC), *organisationAccounts[0].DeletedOn)
+		assert.Nil(t, organisationAccounts[1].DeletedOn)
+	}
+
+	onlyDeleted := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("filter[only_deleted]"))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer onlyDeleted.Close()
+
+	_, err = NewClient(onlyDeleted.URL).List(context.Background(), OnlyDeleted())
+
+	assert.NoError(t, err)
+}
+
+func TestSelectFields(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("fields[accounts]")
+		_, _ = w.Write([]byte(`{"data":[{"id":"ad27e265-9605-4b4b-a0e5-3003ea9cc4dc","attributes":{"account_number":"41426819"}}]}`))
+	}))
+	defer ts.Close()
+
+	for _, client := range []*Client{NewClient(ts.URL), NewClient(ts.URL, WithStrictDecoding())} {
+		accounts, err := client.List(context.Background(), SelectFields("id", "account_number"))
+		assert.NoError(t, err)
+		assert.Equal(t, "id,account_number", query)
+		assert.Equal(t, []OrganisationAccount{{
+			ID:         uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+			Attributes: OrganisationAccountAttributes{AccountNumber: String("41426819")},
+		}}, accounts)
+	}
+
+	_, err := NewClient(ts.URL).List(context.Background(), SelectFields("id", "sort_code"))
+	assert.True(t, errors.Is(err, ErrUnknownField))
+}
+
+func TestSparseFieldset(t *testing.T) {
+	accounts := newTestAccounts(3)
+
+	paging := newPagingServer(accounts)
+	defer paging.Close()
+
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("fields[accounts]"))
+		paging.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	listed, err := NewClient(ts.URL, WithStrictDecoding()).ListAll(
+		context.Background(),
+		SparseFieldset("id", "type"),
+		PageSizeListOption(2),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, accounts, listed)
+	assert.Equal(t, []string{"id,type", "id,type"}, queries)
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"data":{"type":"accounts","attributes":{"bank_id":"400300","sort_code":"400300"}},
+			"links":{"self":"/v1/organisation/accounts"}
+		}`))
+	}))
+	defer ts.Close()
+
+	account, err := NewClient(ts.URL).Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, "400300", account.Attributes.BankID)
+
+	_, err = NewClient(ts.URL, WithStrictDecoding()).Fetch(context.Background(), uuid.New())
+	assert.Error(t, err)
+
+	// once aliased, the field is known
+	aliases := FieldAliases{"sort_code": "bank_id"}
+	account, err = NewClient(ts.URL, WithStrictDecoding(), WithFieldAliases(aliases)).Fetch(context.Background(), uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, "400300", account.Attributes.BankID)
+}
+
+func TestWithListGuard(t *testing.T) {
+	ts := newPagingServer(newTestAccounts(5))
+	defer ts.Close()
+
+	testCases := []struct {
+		name        string
+		guard       int
+		options     []ListOption
+		expectedErr error
+	}{
+		{
+			name:        "Not OK - unfiltered list over the guard",
+			guard:       4,
+			expectedErr: ErrListGuardExceeded,
+		},
+		{
+			name:  "OK - unfiltered list at the guard",
+			guard: 5,
+		},
+		{
+			name:    "OK - filtered list over the guard",
+			guard:   4,
+			options: []ListOption{FilterByAccountClassification(ClassificationPersonal)},
+		},
+		{
+			name:        "Not OK - paged list over the guard",
+			guard:       4,
+			options:     []ListOption{PageSizeListOption(5)},
+			expectedErr: ErrListGuardExceeded,
+		},
+		{
+			name:    "OK - no guard",
+			options: []ListOption{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(ts.URL, WithListGuard(tc.guard))
+
+			accounts, err := client.List(context.Background(), tc.options...)
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+				assert.Contains(t, err.Error(), "5 accounts")
+				assert.Nil(t, accounts)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, accounts, 5)
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	id := uuid.New()
+
+	var status, versionRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			versionRequests++
+			_, _ = w.Write([]byte(`{"version":"v1.0.0"}`))
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"data":{"id":"` + id.String() + `"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(
+		ts.URL,
+		WithStaleOnError(time.Hour),
+		WithVersionCheck(),
+		WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 1)),
+	)
+	var retries int
+	client.OnRetry(func(RetryEvent) { retries++ })
+	ctx := context.Background()
+
+	status = http.StatusOK
+	_, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+
+	reset := client.Reset()
+	assert.NotSame(t, client, reset)
+	assert.Equal(t, ClientStats{}, reset.Stats())
+	assert.Equal(t, int64(1), client.Stats().TotalRequests)
+
+	// the reset client has no stale data to fall back to, and checks the version again
+	status = http.StatusServiceUnavailable
+	_, err = reset.Fetch(ctx, id)
+	assert.False(t, errors.Is(err, ErrStaleData))
+	assert.Equal(t, 2, versionRequests)
+	assert.Equal(t, 0, retries, "retry observers are not carried over")
+
+	// while the original client is unchanged
+	_, err = client.Fetch(ctx, id)
+	assert.True(t, errors.Is(err, ErrStaleData))
+	assert.Equal(t, 2, versionRequests)
+	assert.NotZero(t, retries)
+
+	// the configuration is kept
+	assert.Equal(t, int64(1), reset.Stats().RetryCount)
 }
@@ -0,0 +1,53 @@
+package form3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotDiff(t *testing.T) {
+	account := OrganisationAccount{
+		ID:      uuid.New(),
+		Version: 0,
+		Attributes: OrganisationAccountAttributes{
+			Country: CountryGB,
+			IBAN:    String("GB29NWBK60161331926819"),
+			Name:    []string{"Jane Doe"},
+		},
+	}
+
+	before := account.Snapshot()
+
+	account.Version = 1
+	account.Attributes.Name[0] = "Jane Smith"
+	account.Attributes.IBAN = nil
+	account.Attributes.Switched = Bool(true)
+	account.ModifiedBy = "john.doe@example.com"
+	deletedOn := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	account.DeletedOn = &deletedOn
+
+	after := account.Snapshot()
+
+	assert.Equal(t, []string{"Jane Doe"}, before.Name)
+	assert.Equal(t, []FieldChange{
+		{Field: "version", OldValue: "0", NewValue: "1"},
+		{Field: "iban", OldValue: "GB29NWBK60161331926819", NewValue: ""},
+		{Field: "name", OldValue: `["Jane Doe"]`, NewValue: `["Jane Smith"]`},
+		{Field: "switched", OldValue: "false", NewValue: "true"},
+		{Field: "modified_by", OldValue: "", NewValue: "john.doe@example.com"},
+		{Field: "deleted_on", OldValue: "", NewValue: "2021-03-04T05:06:07Z"},
+	}, Diff(before, after))
+	assert.Empty(t, Diff(after, after))
+}
+
+func TestSnapshotDiffAmbiguousNames(t *testing.T) {
+	before := OrganisationAccount{Attributes: OrganisationAccountAttributes{Name: []string{"a, b"}}}.Snapshot()
+	after := OrganisationAccount{Attributes: OrganisationAccountAttributes{Name: []string{"a", "b"}}}.Snapshot()
+
+	assert.Equal(t, []FieldChange{
+		{Field: "name", OldValue: `["a, b"]`, NewValue: `["a","b"]`},
+	}, Diff(before, after))
+}
@@ -0,0 +1,134 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// staleCache keeps the last successful result of read requests, keyed by their URL,
+// to serve them when the API is unavailable. Entries older than the TTL are dropped when
+// they are looked up, and all at once at most every TTL, so that the cache only grows
+// with the URLs requested recently.
+type staleCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]staleEntry
+	lastSweep time.Time
+}
+
+// staleEntry is a result kept by the stale cache, along with when it was received.
+type staleEntry struct {
+	value      interface{}
+	receivedAt time.Time
+}
+
+func newStaleCache(ttl time.Duration) *staleCache {
+	return &staleCache{
+		ttl:     ttl,
+		entries: map[string]staleEntry{},
+	}
+}
+
+// resolve keeps the value received for the given key when the request succeeded. When
+// it failed because the API is unavailable, it returns the value last kept for the key
+// instead, if it is recent enough, along with an error wrapping both ErrStaleData and
+// the original error. Otherwise, it returns the value and error as they are.
+func (s *staleCache) resolve(key string, value interface{}, err error) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		now := time.Now()
+		s.entries[key] = staleEntry{value: value, receivedAt: now}
+		if now.Sub(s.lastSweep) > s.ttl {
+			s.sweep(now)
+		}
+		return value, nil
+	}
+
+	if !isUnavailable(err) {
+		return value, err
+	}
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return value, err
+	}
+	if time.Since(entry.receivedAt) > s.ttl {
+		delete(s.entries, key)
+		return value, err
+	}
+
+	return entry.value, &staleDataError{err: err, receivedAt: entry.receivedAt}
+}
+
+// sweep drops the entries that are too old to be served anymore.
+func (s *staleCache) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if now.Sub(entry.receivedAt) > s.ttl {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// forget drops the entries kept for the given URL, whatever their query string, e.g.
+// once the resource it points to changed.
+func (s *staleCache) forget(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if key == url || strings.HasPrefix(key, url+"?") {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// isUnavailable reports whether the error means that the API could not be reached or
+// kept failing, as opposed to rejecting the request, the caller giving up, or the client
+// failing to handle the response.
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || isConnectionReset(err)
+}
+
+// staleDataError is returned along with a stale result. It matches both ErrStaleData
+// and the error that prevented getting a fresh result.
+type staleDataError struct {
+	err        error
+	receivedAt time.Time
+}
+
+func (e *staleDataError) Error() string {
+	return fmt.Sprintf("%s received at %s: %s", ErrStaleData, e.receivedAt.Format(time.RFC3339), e.err)
+}
+
+func (e *staleDataError) Is(target error) bool {
+	return target == ErrStaleData
+}
+
+func (e *staleDataError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,154 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithStaleOnError(t *testing.T) {
+	id := uuid.New()
+
+	var status int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if r.URL.Path == "/v1/organisation/accounts" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + id.String() + `"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"` + id.String() + `"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithStaleOnError(time.Hour), WithMaxRetries(0))
+	ctx := context.Background()
+
+	// nothing to fall back to yet
+	status = http.StatusServiceUnavailable
+	_, err := client.Fetch(ctx, id)
+	assert.False(t, errors.Is(err, ErrStaleData))
+
+	status = http.StatusOK
+	_, err = client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	_, err = client.List(ctx)
+	assert.NoError(t, err)
+
+	status = http.StatusServiceUnavailable
+	organisationAccount, err := client.Fetch(ctx, id)
+	assert.True(t, errors.Is(err, ErrStaleData))
+	assert.True(t, errors.Is(err, &APIError{StatusCode: http.StatusServiceUnavailable}))
+	assert.Equal(t, id, organisationAccount.ID)
+
+	organisationAccounts, err := client.List(ctx)
+	assert.True(t, errors.Is(err, ErrStaleData))
+	assert.Len(t, organisationAccounts, 1)
+
+	// the request itself is wrong, thus there is nothing to fall back from
+	status = http.StatusNotFound
+	_, err = client.Fetch(ctx, id)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(err, ErrStaleData))
+}
+
+func TestStaleCacheExpiry(t *testing.T) {
+	cache := newStaleCache(time.Millisecond)
+	down := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	_, err := cache.resolve("key", 1, nil)
+	assert.NoError(t, err)
+
+	value, err := cache.resolve("key", 0, down)
+	assert.True(t, errors.Is(err, ErrStaleData))
+	assert.Equal(t, 1, value)
+
+	time.Sleep(5 * time.Millisecond)
+
+	value, err = cache.resolve("key", 0, down)
+	assert.Equal(t, error(down), err)
+	assert.Equal(t, 0, value)
+}
+
+func TestIsUnavailable(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         error
+		unavailable bool
+	}{
+		{"network error", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{"connection reset", fmt.Errorf("read: %w", &net.OpError{Op: "read", Err: syscall.ECONNRESET}), true},
+		{"retries exhausted", &RetryError{Attempts: 3}, true},
+		{"server error", &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"client error", &APIError{StatusCode: http.StatusNotFound}, false},
+		{"canceled", context.Canceled, false},
+		{"closed client", ErrClientClosed, false},
+		{"decoding error", &json.SyntaxError{}, false},
+		{"hook error", errors.New("rejected by hook"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.unavailable, isUnavailable(tc.err))
+		})
+	}
+}
+
+func TestStaleCacheSweep(t *testing.T) {
+	cache := newStaleCache(time.Millisecond)
+
+	_, err := cache.resolve("first", 1, nil)
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// storing a result drops the entries that expired meanwhile
+	_, err = cache.resolve("second", 2, nil)
+	assert.NoError(t, err)
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, "second")
+}
+
+func TestWithStaleOnErrorAfterDelete(t *testing.T) {
+	id := uuid.New()
+
+	var status int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"data":{"id":"` + id.String() + `"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithStaleOnError(time.Hour), WithMaxRetries(0))
+	ctx := context.Background()
+
+	status = http.StatusOK
+	_, err := client.Fetch(ctx, id)
+	assert.NoError(t, err)
+	_, err = client.Fetch(ctx, id, IncludeRelatedFetch("organisation"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Delete(ctx, id, 0))
+
+	// the deleted account is not served as stale data
+	status = http.StatusServiceUnavailable
+	_, err = client.Fetch(ctx, id)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrStaleData))
+	_, err = client.Fetch(ctx, id, IncludeRelatedFetch("organisation"))
+	assert.False(t, errors.Is(err, ErrStaleData))
+}
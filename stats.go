@@ -0,0 +1,122 @@
+package form3
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ClientStats is a snapshot of the statistics accumulated by a client
+// since it was created or since the last call to ResetStats.
+type ClientStats struct {
+	// TotalRequests is the number of requests made by the client's methods,
+	// each of them counted once regardless of how many times it was retried.
+	TotalRequests int64
+	// SuccessfulRequests is the number of requests that ended with a status code below 300.
+	SuccessfulRequests int64
+	// FailedRequests is the number of requests that ended with an error or a
+	// status code of 300 or above.
+	FailedRequests int64
+	// RetryCount is the number of times requests were retried.
+	RetryCount int64
+	// TotalBytesReceived is the sum of the Content-Length of all responses that had one.
+	TotalBytesReceived int64
+	// TotalBytesSent is the sum of the Content-Length of all requests that had one.
+	TotalBytesSent int64
+	// TotalRequestBodyBytes is the number of bytes of request bodies actually sent,
+	// retries included, whether the requests had a Content-Length or not.
+	TotalRequestBodyBytes int64
+	// TotalResponseBodyBytes is the number of bytes of response bodies actually read,
+	// whether the responses had a Content-Length or not.
+	TotalResponseBodyBytes int64
+	// AverageLatencyNs is the average time requests took, retries included, in nanoseconds.
+	AverageLatencyNs int64
+}
+
+// clientStats holds the counters behind ClientStats, which are updated atomically.
+type clientStats struct {
+	totalRequests      int64
+	successfulRequests int64
+	failedRequests     int64
+	retryCount         int64
+	totalBytesReceived int64
+	totalBytesSent     int64
+	totalLatencyNs     int64
+
+	totalRequestBodyBytes  int64
+	totalResponseBodyBytes int64
+}
+
+// Stats returns a snapshot of the statistics accumulated by the client.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{
+		TotalRequests:      atomic.LoadInt64(&c.stats.totalRequests),
+		SuccessfulRequests: atomic.LoadInt64(&c.stats.successfulRequests),
+		FailedRequests:     atomic.LoadInt64(&c.stats.failedRequests),
+		RetryCount:         atomic.LoadInt64(&c.stats.retryCount),
+		TotalBytesReceived: atomic.LoadInt64(&c.stats.totalBytesReceived),
+		TotalBytesSent:     atomic.LoadInt64(&c.stats.totalBytesSent),
+
+		TotalRequestBodyBytes:  atomic.LoadInt64(&c.stats.totalRequestBodyBytes),
+		TotalResponseBodyBytes: atomic.LoadInt64(&c.stats.totalResponseBodyBytes),
+	}
+
+	if stats.TotalRequests > 0 {
+		stats.AverageLatencyNs = atomic.LoadInt64(&c.stats.totalLatencyNs) / stats.TotalRequests
+	}
+
+	return stats
+}
+
+// ResetStats sets all the statistics accumulated by the client back to zero.
+func (c *Client) ResetStats() {
+	atomic.StoreInt64(&c.stats.totalRequests, 0)
+	atomic.StoreInt64(&c.stats.successfulRequests, 0)
+	atomic.StoreInt64(&c.stats.failedRequests, 0)
+	atomic.StoreInt64(&c.stats.retryCount, 0)
+	atomic.StoreInt64(&c.stats.totalBytesReceived, 0)
+	atomic.StoreInt64(&c.stats.totalBytesSent, 0)
+	atomic.StoreInt64(&c.stats.totalLatencyNs, 0)
+	atomic.StoreInt64(&c.stats.totalRequestBodyBytes, 0)
+	atomic.StoreInt64(&c.stats.totalResponseBodyBytes, 0)
+}
+
+// recordAttempt accounts for the bytes transferred by a single attempt of a request.
+func (s *clientStats) recordAttempt(req *http.Request, resp *http.Response) {
+	if req.ContentLength > 0 {
+		atomic.AddInt64(&s.totalBytesSent, req.ContentLength)
+	}
+
+	if resp != nil && resp.ContentLength > 0 {
+		atomic.AddInt64(&s.totalBytesReceived, resp.ContentLength)
+	}
+}
+
+// recordRequest accounts for a request once the client stopped retrying it.
+func (s *clientStats) recordRequest(attempts int, resp *http.Response, err error, elapsed time.Duration) {
+	atomic.AddInt64(&s.totalRequests, 1)
+	atomic.AddInt64(&s.totalLatencyNs, int64(elapsed))
+
+	if attempts > 1 {
+		atomic.AddInt64(&s.retryCount, int64(attempts-1))
+	}
+
+	if err == nil && resp != nil && resp.StatusCode < 300 {
+		atomic.AddInt64(&s.successfulRequests, 1)
+	} else {
+		atomic.AddInt64(&s.failedRequests, 1)
+	}
+}
+
+// countingReadCloser adds the number of bytes read through it to a counter.
+type countingReadCloser struct {
+	io.ReadCloser
+	count *int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.count, int64(n))
+	return n, err
+}
@@ -0,0 +1,78 @@
+package form3
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	var requests int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		switch {
+		case requests == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithBackoffStrategy(LinearBackoff(time.Millisecond, 0, 3)))
+
+	_, err := client.List(context.Background())
+	assert.NoError(t, err)
+
+	err = client.Delete(context.Background(), uuid.New(), 0)
+	assert.Error(t, err)
+
+	stats := client.Stats()
+	assert.Equal(t, int64(2), stats.TotalRequests)
+	assert.Equal(t, int64(1), stats.SuccessfulRequests)
+	assert.Equal(t, int64(1), stats.FailedRequests)
+	assert.Equal(t, int64(1), stats.RetryCount)
+	assert.Equal(t, int64(len(`{"data":[]}`)), stats.TotalBytesReceived)
+	assert.Greater(t, stats.AverageLatencyNs, int64(0))
+
+	client.ResetStats()
+
+	assert.Equal(t, ClientStats{}, client.Stats())
+}
+
+func TestStatsBodyBytes(t *testing.T) {
+	var received int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received += len(body)
+
+		// flushing before writing the body leaves the response without a Content-Length
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(`{"data":`))
+		_, _ = w.Write(body)
+		_, _ = w.Write([]byte(`}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	_, err := client.Create(context.Background(), newTestAccounts(1)[0])
+	assert.NoError(t, err)
+
+	stats := client.Stats()
+	assert.Greater(t, received, 0)
+	assert.Equal(t, int64(received), stats.TotalRequestBodyBytes)
+	assert.Equal(t, int64(received+len(`{"data":}`)), stats.TotalResponseBodyBytes)
+	assert.Equal(t, int64(0), stats.TotalBytesReceived)
+}
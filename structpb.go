@@ -0,0 +1,47 @@
+package form3
+
+import (
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// AccountToStructPB converts the organisation account to a protobuf Struct, e.g. to
+// expose it over gRPC. The conversion goes through JSON, so that the keys of the Struct
+// are the JSON field names of the account.
+func AccountToStructPB(a OrganisationAccount) (*structpb.Struct, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &structpb.Struct{}
+	err = s.UnmarshalJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// AccountFromStructPB converts a protobuf Struct shaped like the JSON representation
+// of an organisation account, e.g. as returned by AccountToStructPB, back to an account.
+func AccountFromStructPB(s *structpb.Struct) (OrganisationAccount, error) {
+	if s == nil {
+		return OrganisationAccount{}, errors.New("nil struct")
+	}
+
+	b, err := s.MarshalJSON()
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	var a OrganisationAccount
+	err = json.Unmarshal(b, &a)
+	if err != nil {
+		return OrganisationAccount{}, err
+	}
+
+	return a, nil
+}
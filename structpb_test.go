@@ -0,0 +1,50 @@
+package form3
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestAccountStructPB(t *testing.T) {
+	createdOn := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	account := OrganisationAccount{
+		ID:             uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		Type:           AccountType,
+		OrganisationID: uuid.MustParse("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+		Version:        3,
+		CreatedOn:      &createdOn,
+		Attributes: OrganisationAccountAttributes{
+			Country:       "GB",
+			AccountNumber: String("41426819"),
+			Name:          []string{"Samantha Holder"},
+			JointAccount:  Bool(false),
+		},
+	}
+
+	s, err := AccountToStructPB(account)
+	assert.NoError(t, err)
+	assert.Equal(t, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", s.Fields["id"].GetStringValue())
+	assert.Equal(t, float64(3), s.Fields["version"].GetNumberValue())
+
+	attributes := s.Fields["attributes"].GetStructValue()
+	assert.Equal(t, "41426819", attributes.Fields["account_number"].GetStringValue())
+	assert.Equal(t, "Samantha Holder", attributes.Fields["name"].GetListValue().Values[0].GetStringValue())
+
+	converted, err := AccountFromStructPB(s)
+	assert.NoError(t, err)
+	assert.Equal(t, account, converted)
+}
+
+func TestAccountFromStructPB(t *testing.T) {
+	_, err := AccountFromStructPB(nil)
+	assert.Error(t, err)
+
+	_, err = AccountFromStructPB(&structpb.Struct{Fields: map[string]*structpb.Value{
+		"id": structpb.NewStringValue("not a uuid"),
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package form3
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// synchronizedClient is an AccountService that serialises writes while letting reads
+// run in parallel.
+type synchronizedClient struct {
+	inner AccountService
+	mu    sync.RWMutex
+}
+
+// NewSynchronizedClient wraps the given account service so that Create and Delete
+// never run concurrently with any other call, while Fetch and List can run in
+// parallel with each other. This is useful e.g. when managing a fixed pool of
+// accounts, or when goroutines share a fake account service that is not safe for
+// concurrent use.
+func NewSynchronizedClient(inner AccountService) AccountService {
+	return &synchronizedClient{inner: inner}
+}
+
+// Create creates the organisation account while holding the write lock.
+func (s *synchronizedClient) Create(ctx context.Context, organisationAccount OrganisationAccount, roo ...RequestOption) (OrganisationAccount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inner.Create(ctx, organisationAccount, roo...)
+}
+
+// Fetch fetches the organisation account while holding the read lock.
+func (s *synchronizedClient) Fetch(ctx context.Context, accountID uuid.UUID, roo ...RequestOption) (OrganisationAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.inner.Fetch(ctx, accountID, roo...)
+}
+
+// List lists the organisation accounts while holding the read lock.
+func (s *synchronizedClient) List(ctx context.Context, loo ...ListOption) ([]OrganisationAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.inner.List(ctx, loo...)
+}
+
+// Delete deletes the organisation account while holding the write lock.
+func (s *synchronizedClient) Delete(ctx context.Context, accountID uuid.UUID, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inner.Delete(ctx, accountID, version)
+}
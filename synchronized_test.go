@@ -0,0 +1,81 @@
+package form3
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAccountService is an in-memory AccountService that is not safe for concurrent
+// use on its own.
+type fakeAccountService struct {
+	accounts map[uuid.UUID]OrganisationAccount
+}
+
+func newFakeAccountService() *fakeAccountService {
+	return &fakeAccountService{accounts: map[uuid.UUID]OrganisationAccount{}}
+}
+
+func (f *fakeAccountService) Create(_ context.Context, organisationAccount OrganisationAccount, _ ...RequestOption) (OrganisationAccount, error) {
+	f.accounts[organisationAccount.ID] = organisationAccount
+	return organisationAccount, nil
+}
+
+func (f *fakeAccountService) Fetch(_ context.Context, accountID uuid.UUID, _ ...RequestOption) (OrganisationAccount, error) {
+	organisationAccount, ok := f.accounts[accountID]
+	if !ok {
+		return OrganisationAccount{}, ErrNotFound
+	}
+	return organisationAccount, nil
+}
+
+func (f *fakeAccountService) List(_ context.Context, _ ...ListOption) ([]OrganisationAccount, error) {
+	var organisationAccounts []OrganisationAccount
+	for _, organisationAccount := range f.accounts {
+		organisationAccounts = append(organisationAccounts, organisationAccount)
+	}
+	return organisationAccounts, nil
+}
+
+func (f *fakeAccountService) Delete(_ context.Context, accountID uuid.UUID, _ int) error {
+	if _, ok := f.accounts[accountID]; !ok {
+		return ErrNotFound
+	}
+	delete(f.accounts, accountID)
+	return nil
+}
+
+func TestSynchronizedClient(t *testing.T) {
+	ctx := context.Background()
+	client := NewSynchronizedClient(newFakeAccountService())
+
+	// the fake service has no locking of its own, so the race detector flags
+	// any write that is not serialised by the wrapper
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id := uuid.New()
+			_, err := client.Create(ctx, OrganisationAccount{ID: id})
+			assert.NoError(t, err)
+
+			_, err = client.Fetch(ctx, id)
+			assert.NoError(t, err)
+
+			_, err = client.List(ctx)
+			assert.NoError(t, err)
+
+			assert.NoError(t, client.Delete(ctx, id, 0))
+		}()
+	}
+	wg.Wait()
+
+	organisationAccounts, err := client.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, organisationAccounts)
+}
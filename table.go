@@ -0,0 +1,105 @@
+package form3
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// defaultTableCellWidth is the default maximum width of the cells written by WriteTable,
+// long enough for IDs to be shown in full.
+const defaultTableCellWidth = 36
+
+var (
+	// WithColumns is a WriteTable option to only show the given columns, in the given
+	// order. Columns are named after CSVHeader, e.g. "id" or "iban". Naming an unknown
+	// column makes WriteTable return ErrUnknownField.
+	WithColumns = func(columns ...string) TableOption {
+		return func(to *tableOptions) {
+			to.columns = columns
+		}
+	}
+
+	// WithMaxCellWidth is a WriteTable option to truncate the values longer than
+	// width characters, instead of the default of 36.
+	WithMaxCellWidth = func(width int) TableOption {
+		return func(to *tableOptions) {
+			to.maxCellWidth = width
+		}
+	}
+)
+
+type tableOptions struct {
+	columns      []string
+	maxCellWidth int
+}
+
+// TableOption is a function that can change how WriteTable lays out the accounts.
+type TableOption = func(*tableOptions)
+
+// WriteTable writes the accounts to w as a text table meant to be read by humans, e.g.
+// in a terminal: a header row followed by a row per account, with aligned columns.
+// Columns are those of CSVHeader, and cell values those of ToCSVRow, with values longer
+// than the maximum cell width truncated with an ellipsis.
+func WriteTable(w io.Writer, accounts []OrganisationAccount, too ...TableOption) error {
+	options := tableOptions{
+		columns:      CSVHeader,
+		maxCellWidth: defaultTableCellWidth,
+	}
+	for _, to := range too {
+		to(&options)
+	}
+
+	positions := make(map[string]int, len(CSVHeader))
+	for i, column := range CSVHeader {
+		positions[column] = i
+	}
+
+	indexes := make([]int, len(options.columns))
+	for i, column := range options.columns {
+		position, ok := positions[column]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownField, column)
+		}
+		indexes[i] = position
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	cells := make([]string, len(indexes))
+	writeRow := func(row []string) error {
+		for i, index := range indexes {
+			cells[i] = tableCell(row[index], options.maxCellWidth)
+		}
+		_, err := fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		return err
+	}
+
+	err := writeRow(CSVHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		err = writeRow(account.ToCSVRow())
+		if err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// tableCell returns the value as a table cell of at most maxWidth characters, if
+// maxWidth is positive, replacing the characters that would break the layout.
+func tableCell(value string, maxWidth int) string {
+	value = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+
+	runes := []rune(value)
+	if maxWidth > 0 && len(runes) > maxWidth {
+		return string(runes[:maxWidth-1]) + "…"
+	}
+
+	return value
+}
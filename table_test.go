@@ -0,0 +1,77 @@
+package form3
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTable(t *testing.T) {
+	accounts := []OrganisationAccount{
+		{
+			ID: uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+			Attributes: OrganisationAccountAttributes{
+				Country: "GB",
+				IBAN:    String("GB11NWBK40030041426819"),
+				Name:    []string{"Samantha Holder"},
+			},
+		},
+		{
+			ID: uuid.MustParse("5d8a4f1c-8ee9-4f6b-9d3b-7d2f8f1e6a10"),
+			Attributes: OrganisationAccountAttributes{
+				Country: "FR",
+				Name:    []string{"Jean\tDupont"},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		options  []TableOption
+		expected string
+	}{
+		{
+			name:    "OK - selected columns",
+			options: []TableOption{WithColumns("country", "name", "iban")},
+			expected: "country  name             iban\n" +
+				"GB       Samantha Holder  GB11NWBK40030041426819\n" +
+				"FR       Jean Dupont      \n",
+		},
+		{
+			name:    "OK - truncated cells",
+			options: []TableOption{WithColumns("id", "iban"), WithMaxCellWidth(12)},
+			expected: "id            iban\n" +
+				"ad27e265-96…  GB11NWBK400…\n" +
+				"5d8a4f1c-8e…  \n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := WriteTable(&buf, accounts, tc.options...)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteTableAllColumns(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTable(&buf, newTestAccounts(2))
+	assert.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	assert.Len(t, lines, 3)
+	assert.Len(t, bytes.Fields(lines[0]), len(CSVHeader))
+}
+
+func TestWriteTableUnknownColumn(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTable(&buf, nil, WithColumns("id", "sort_code"))
+	assert.True(t, errors.Is(err, ErrUnknownField))
+	assert.Empty(t, buf.String())
+}
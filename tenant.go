@@ -0,0 +1,26 @@
+package form3
+
+import (
+	"context"
+)
+
+// defaultTenantHeader is the header carrying the tenant ID of requests, unless
+// WithTenantHeader sets another one.
+const defaultTenantHeader = "X-Tenant-ID"
+
+// tenantKey is the key of the context value holding the tenant ID.
+type tenantKey struct{}
+
+// TenantContext returns a copy of ctx holding the ID of the tenant on whose behalf
+// requests are made, e.g. by SaaS platforms serving several tenants with a single
+// client. Requests made with the returned context send the tenant ID in the
+// X-Tenant-ID header, or in the header set by WithTenantHeader.
+func TenantContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// tenantFromContext returns the tenant ID stored in ctx by TenantContext, if any.
+func tenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}
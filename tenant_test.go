@@ -0,0 +1,63 @@
+package form3
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantContext(t *testing.T) {
+	var headers []http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header)
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		name           string
+		ctx            context.Context
+		options        []ClientOption
+		expectedHeader string
+		expectedValue  string
+	}{
+		{
+			name:           "OK - default header",
+			ctx:            TenantContext(context.Background(), "tenant-1"),
+			expectedHeader: "X-Tenant-ID",
+			expectedValue:  "tenant-1",
+		},
+		{
+			name:           "OK - custom header",
+			ctx:            TenantContext(context.Background(), "tenant-2"),
+			options:        []ClientOption{WithTenantHeader("X-Org-Scope")},
+			expectedHeader: "X-Org-Scope",
+			expectedValue:  "tenant-2",
+		},
+		{
+			name:           "OK - no tenant",
+			ctx:            context.Background(),
+			expectedHeader: "X-Tenant-ID",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			headers = nil
+
+			_, err := NewClient(ts.URL, tc.options...).List(tc.ctx)
+			assert.NoError(t, err)
+
+			if assert.Len(t, headers, 1) {
+				assert.Equal(t, tc.expectedValue, headers[0].Get(tc.expectedHeader))
+				if tc.expectedValue == "" {
+					_, ok := headers[0][tc.expectedHeader]
+					assert.False(t, ok)
+				}
+			}
+		})
+	}
+}
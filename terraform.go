@@ -0,0 +1,72 @@
+package form3
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// terraformResourceType is the type of the resource managing organisation accounts
+// in the Form3 Terraform provider.
+const terraformResourceType = "form3_account"
+
+// terraformAccount holds the arguments of a form3_account Terraform resource.
+type terraformAccount struct {
+	AccountID               string   `json:"account_id"`
+	OrganisationID          string   `json:"organisation_id"`
+	Country                 string   `json:"country"`
+	BaseCurrency            string   `json:"base_currency,omitempty"`
+	AccountNumber           string   `json:"account_number,omitempty"`
+	BankID                  string   `json:"bank_id,omitempty"`
+	BankIDCode              string   `json:"bank_id_code,omitempty"`
+	BIC                     string   `json:"bic,omitempty"`
+	IBAN                    string   `json:"iban,omitempty"`
+	Name                    []string `json:"name,omitempty"`
+	AlternativeNames        []string `json:"alternative_names,omitempty"`
+	AccountClassification   string   `json:"account_classification,omitempty"`
+	JointAccount            *bool    `json:"joint_account,omitempty"`
+	AccountMatchingOptOut   bool     `json:"account_matching_opt_out"`
+	SecondaryIdentification string   `json:"secondary_identification,omitempty"`
+	Switched                *bool    `json:"switched,omitempty"`
+}
+
+// ToTerraformJSON returns the account as a form3_account resource written in the
+// JSON syntax of Terraform, e.g. to bring existing accounts under Terraform.
+// The resource is named after the account ID, e.g. account_ad27e265_9605_....
+//
+// Its arguments are named after the JSON fields of the REST API, except that:
+//   - id becomes account_id, since id is reserved by Terraform
+//   - type and version are left out, since Terraform tracks them itself
+//   - attributes are flattened into the resource, e.g. attributes.bic becomes bic
+//   - optional fields that are not set are left out
+func (o OrganisationAccount) ToTerraformJSON() ([]byte, error) {
+	attrs := o.Attributes
+
+	resource := terraformAccount{
+		AccountID:               o.ID.String(),
+		OrganisationID:          o.OrganisationID.String(),
+		Country:                 string(attrs.Country),
+		BaseCurrency:            attrs.BaseCurrency,
+		AccountNumber:           stringValue(attrs.AccountNumber),
+		BankID:                  attrs.BankID,
+		BankIDCode:              string(attrs.BankIDCode),
+		BIC:                     stringValue(attrs.BIC),
+		IBAN:                    stringValue(attrs.IBAN),
+		Name:                    attrs.Name,
+		AlternativeNames:        attrs.AlternativeNames,
+		AccountClassification:   string(attrs.AccountClassification),
+		JointAccount:            attrs.JointAccount,
+		AccountMatchingOptOut:   attrs.AccountMatchingOptOut,
+		SecondaryIdentification: stringValue(attrs.SecondaryIdentification),
+		Switched:                attrs.Switched,
+	}
+
+	name := "account_" + strings.ReplaceAll(o.ID.String(), "-", "_")
+
+	return json.MarshalIndent(map[string]interface{}{
+		"resource": map[string]interface{}{
+			terraformResourceType: map[string]terraformAccount{
+				name: resource,
+			},
+		},
+	}, "", "  ")
+}
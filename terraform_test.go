@@ -0,0 +1,48 @@
+package form3
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToTerraformJSON(t *testing.T) {
+	account := OrganisationAccount{
+		ID:             uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"),
+		Type:           AccountType,
+		OrganisationID: uuid.MustParse("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+		Version:        3,
+		Attributes: OrganisationAccountAttributes{
+			Country:      CountryGB,
+			BaseCurrency: "GBP",
+			BankID:       "400300",
+			BankIDCode:   BankIDCodeGBDSC,
+			BIC:          String("NWBKGB22"),
+			Name:         []string{"Jane Doe"},
+			JointAccount: Bool(false),
+		},
+	}
+
+	got, err := account.ToTerraformJSON()
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"resource": {
+			"form3_account": {
+				"account_ad27e265_9605_4b4b_a0e5_3003ea9cc4dc": {
+					"account_id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+					"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+					"country": "GB",
+					"base_currency": "GBP",
+					"bank_id": "400300",
+					"bank_id_code": "GBDSC",
+					"bic": "NWBKGB22",
+					"name": ["Jane Doe"],
+					"joint_account": false,
+					"account_matching_opt_out": false
+				}
+			}
+		}
+	}`, string(got))
+}
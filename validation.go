@@ -0,0 +1,233 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ClassificationPersonal is the classification of accounts held by individuals.
+	ClassificationPersonal AccountClassification = "Personal"
+	// ClassificationBusiness is the classification of accounts held by businesses.
+	ClassificationBusiness AccountClassification = "Business"
+
+	// maxNames is the maximum number of elements the Form3 API accepts in Name.
+	maxNames = 4
+	// maxNameLength is the maximum number of characters of a single Name element.
+	maxNameLength = 140
+)
+
+// AccountClassification tells whether an account is held by an individual
+// or by a business.
+type AccountClassification string
+
+// FieldError describes why a single field of an organisation account is invalid.
+type FieldError struct {
+	Field   string
+	Message string
+	Value   interface{}
+
+	// Value may hold a slice, thus comparing field errors with == could panic: this
+	// makes them incomparable, so that errors.Is relies on their Is method instead
+	_ [0]func()
+}
+
+// Error returns the field name followed by the reason it is invalid.
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Is reports whether target is a FieldError for the same field and reason,
+// whatever the invalid value.
+func (e FieldError) Is(target error) bool {
+	t, ok := target.(FieldError)
+	if !ok {
+		return false
+	}
+
+	return e.Field == t.Field && e.Message == t.Message
+}
+
+// ValidationError is returned by Validate and carries every field error
+// found in the organisation account, so callers can fix all of them at once.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns a summary containing the number of invalid fields and
+// the reason each of them is invalid.
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		messages = append(messages, fe.Error())
+	}
+
+	return fmt.Sprintf(
+		"invalid organisation account (%d errors): %s",
+		len(e.Errors),
+		strings.Join(messages, "; "),
+	)
+}
+
+// Unwrap returns the individual field errors, so that errors.As and errors.Is
+// can inspect each of them.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		errs = append(errs, fe)
+	}
+
+	return errs
+}
+
+// Is reports whether any of the field errors matches target. Unlike Unwrap, which
+// errors.Is only inspects from Go 1.20, it works with every Go version.
+func (e *ValidationError) Is(target error) bool {
+	for _, fe := range e.Errors {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first field error that matches target, like Is does for errors.Is.
+func (e *ValidationError) As(target interface{}) bool {
+	for _, fe := range e.Errors {
+		if errors.As(fe, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks the organisation account against the limits documented by
+// the Form3 API, so that obvious mistakes can be caught before making a request.
+// It collects every invalid field and returns them in a *ValidationError.
+func (o OrganisationAccount) Validate() error {
+	var fieldErrors []FieldError
+
+	if o.ID == uuid.Nil {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "id",
+			Message: "must be set to a non-nil UUID",
+			Value:   o.ID,
+		})
+	}
+
+	if o.OrganisationID == uuid.Nil {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "organisation_id",
+			Message: "must be set to a non-nil UUID",
+			Value:   o.OrganisationID,
+		})
+	}
+
+	if o.Type != AccountType {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "type",
+			Message: fmt.Sprintf("must be %q", AccountType),
+			Value:   o.Type,
+		})
+	}
+
+	names := o.Attributes.Name
+	if len(names) > maxNames {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "name",
+			Message: fmt.Sprintf("must have at most %d elements", maxNames),
+			Value:   names,
+		})
+	}
+
+	for i, name := range names {
+		if utf8.RuneCountInString(name) > maxNameLength {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   fmt.Sprintf("name[%d]", i),
+				Message: fmt.Sprintf("must be at most %d characters long", maxNameLength),
+				Value:   name,
+			})
+		}
+	}
+
+	if len(names) == 0 && o.Attributes.AccountClassification == ClassificationPersonal {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   "name",
+			Message: "must not be empty for personal accounts",
+			Value:   names,
+		})
+	}
+
+	if code := o.Attributes.BankIDCode; code != "" {
+		if _, ok := bankIDCodesByCountry[o.Attributes.Country]; ok && !isValidBankIDCode(o.Attributes.Country, code) {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   "bank_id_code",
+				Message: fmt.Sprintf("is not valid for country %s", o.Attributes.Country),
+				Value:   code,
+			})
+		}
+	}
+
+	if o.Attributes.Country == CountryGB && o.Attributes.BankIDCode == BankIDCodeGBDSC {
+		if err := ValidateSortCode(o.Attributes.BankID); err != nil {
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   "bank_id",
+				Message: err.Error(),
+				Value:   o.Attributes.BankID,
+			})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return &ValidationError{Errors: fieldErrors}
+	}
+
+	return nil
+}
+
+// isValidBankIDCode reports whether code is accepted for accounts held in country.
+func isValidBankIDCode(country Country, code BankIDCode) bool {
+	for _, valid := range bankIDCodesByCountry[country] {
+		if valid == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateSortCode checks that bankID is a valid UK sort code, i.e. exactly
+// 6 digits that are not all zeros.
+func ValidateSortCode(bankID string) error {
+	if len(bankID) != 6 {
+		return errors.New("sort code must be exactly 6 digits")
+	}
+
+	for _, r := range bankID {
+		if r < '0' || r > '9' {
+			return errors.New("sort code must contain only digits")
+		}
+	}
+
+	if bankID == "000000" {
+		return errors.New("sort code must not be all zeros")
+	}
+
+	return nil
+}
+
+// FormatSortCode returns the hyphenated form of a UK sort code (e.g. "12-34-56").
+// If bankID is not a valid sort code, it is returned unchanged.
+func FormatSortCode(bankID string) string {
+	if ValidateSortCode(bankID) != nil {
+		return bankID
+	}
+
+	return bankID[0:2] + "-" + bankID[2:4] + "-" + bankID[4:6]
+}
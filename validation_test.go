@@ -0,0 +1,242 @@
+package form3
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		attributes     OrganisationAccountAttributes
+		expectedFields []string
+	}{
+		{
+			name: "OK - valid personal account",
+			attributes: OrganisationAccountAttributes{
+				Name:                  []string{"Jane Doe"},
+				AccountClassification: ClassificationPersonal,
+			},
+		},
+		{
+			name: "OK - business account without name",
+			attributes: OrganisationAccountAttributes{
+				AccountClassification: ClassificationBusiness,
+			},
+		},
+		{
+			name: "Not OK - personal account without name",
+			attributes: OrganisationAccountAttributes{
+				AccountClassification: ClassificationPersonal,
+			},
+			expectedFields: []string{"name"},
+		},
+		{
+			name: "OK - bank ID code valid for country",
+			attributes: OrganisationAccountAttributes{
+				Country:    CountryDE,
+				BankIDCode: BankIDCodeDEBLZ,
+			},
+		},
+		{
+			name: "OK - bank ID code of unsupported country is left to the API",
+			attributes: OrganisationAccountAttributes{
+				Country:    "ZZ",
+				BankIDCode: "ZZBANK",
+			},
+		},
+		{
+			name: "Not OK - bank ID code not valid for country",
+			attributes: OrganisationAccountAttributes{
+				Country:    CountryGB,
+				BankIDCode: BankIDCodeDEBLZ,
+			},
+			expectedFields: []string{"bank_id_code"},
+		},
+		{
+			name: "Not OK - invalid UK sort code",
+			attributes: OrganisationAccountAttributes{
+				Country:    CountryGB,
+				BankID:     "40030",
+				BankIDCode: BankIDCodeGBDSC,
+			},
+			expectedFields: []string{"bank_id"},
+		},
+		{
+			name: "Not OK - too many names, one of them too long",
+			attributes: OrganisationAccountAttributes{
+				Name: []string{"a", "b", strings.Repeat("c", 141), "d", "e"},
+			},
+			expectedFields: []string{"name", "name[2]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			account := DefaultType()
+			account.ID = uuid.New()
+			account.OrganisationID = uuid.New()
+			account.Attributes = tc.attributes
+
+			err := account.Validate()
+
+			if len(tc.expectedFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			assert.True(t, errors.As(err, &validationErr))
+
+			var fields []string
+			for _, fe := range validationErr.Errors {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tc.expectedFields, fields)
+
+			var fieldErr FieldError
+			assert.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, tc.expectedFields[0], fieldErr.Field)
+		})
+	}
+}
+
+func TestValidateType(t *testing.T) {
+	testCases := []struct {
+		name      string
+		typ       string
+		expectErr bool
+	}{
+		{
+			name: "OK - accounts",
+			typ:  AccountType,
+		},
+		{
+			name:      "Not OK - empty",
+			expectErr: true,
+		},
+		{
+			name:      "Not OK - other type",
+			typ:       "payments",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			account := OrganisationAccount{
+				ID:             uuid.New(),
+				OrganisationID: uuid.New(),
+				Type:           tc.typ,
+				Attributes:     OrganisationAccountAttributes{AccountClassification: ClassificationBusiness},
+			}
+
+			err := account.Validate()
+
+			if !tc.expectErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fieldErr FieldError
+			assert.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, "type", fieldErr.Field)
+		})
+	}
+}
+
+func TestValidateIDs(t *testing.T) {
+	account := DefaultType()
+	account.Attributes.AccountClassification = ClassificationBusiness
+
+	err := account.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	var fields []string
+	for _, fe := range validationErr.Errors {
+		fields = append(fields, fe.Field)
+	}
+	assert.Equal(t, []string{"id", "organisation_id"}, fields)
+}
+
+func TestValidationErrorIs(t *testing.T) {
+	names := []string{"1", "2", "3", "4", "5"}
+	account := OrganisationAccount{
+		ID:             uuid.New(),
+		OrganisationID: uuid.New(),
+		Type:           AccountType,
+		Attributes: OrganisationAccountAttributes{
+			AccountClassification: ClassificationBusiness,
+			Name:                  names,
+		},
+	}
+
+	err := account.Validate()
+
+	// the values are slices, which must not be compared
+	target := FieldError{Field: "name", Message: "must have at most 4 elements", Value: names}
+	assert.True(t, errors.Is(err, target))
+	assert.False(t, errors.Is(err, FieldError{Field: "name", Message: "must not be empty", Value: names}))
+
+	// Is and As let errors.Is and errors.As inspect the field errors before Go 1.20
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.True(t, validationErr.Is(target))
+
+		var fieldErr FieldError
+		assert.True(t, validationErr.As(&fieldErr))
+		assert.Equal(t, "name", fieldErr.Field)
+	}
+}
+
+func TestValidateSortCode(t *testing.T) {
+	testCases := []struct {
+		name              string
+		bankID            string
+		expectErr         bool
+		expectedFormatted string
+	}{
+		{
+			name:              "OK - valid sort code",
+			bankID:            "400300",
+			expectedFormatted: "40-03-00",
+		},
+		{
+			name:              "Not OK - too short",
+			bankID:            "40030",
+			expectErr:         true,
+			expectedFormatted: "40030",
+		},
+		{
+			name:              "Not OK - not numeric",
+			bankID:            "40030A",
+			expectErr:         true,
+			expectedFormatted: "40030A",
+		},
+		{
+			name:              "Not OK - all zeros",
+			bankID:            "000000",
+			expectErr:         true,
+			expectedFormatted: "000000",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSortCode(tc.bankID)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedFormatted, FormatSortCode(tc.bankID))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// SupportedAPIVersion is the version of the Form3 API this package is designed for.
+// Any API version with the same major version is considered compatible.
+const SupportedAPIVersion = "v1"
+
+// versionEndpoint is the path, relative to the base URL, where the API reports its version.
+const versionEndpoint = "/version"
+
+// versionCheck remembers whether the API version was found to be compatible, so that
+// it is only checked once per client.
+type versionCheck struct {
+	mu   sync.Mutex
+	done bool
+	err  error
+}
+
+// CheckVersion fetches the version reported by the API and returns an error wrapping
+// ErrUnsupportedAPIVersion if it is not compatible with SupportedAPIVersion. The
+// version endpoint is expected to respond with e.g. {"version": "v1.2.0"}. Unlike
+// other requests, it is not retried, but it carries the same headers, such as the API
+// key.
+func (c *Client) CheckVersion(ctx context.Context) error {
+	if c.lifecycle.isClosed() {
+		return ErrClientClosed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+versionEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	c.setHeaders(ctx, req, nil)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = c.checkErrorMessage(resp)
+	if err != nil {
+		return err
+	}
+
+	var body struct {
+		Version string `json:"version"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return err
+	}
+
+	if majorVersion(body.Version) != majorVersion(SupportedAPIVersion) {
+		return fmt.Errorf("%w: API reports version %q, client supports %q", ErrUnsupportedAPIVersion, body.Version, SupportedAPIVersion)
+	}
+
+	return nil
+}
+
+// checkVersionOnce runs CheckVersion if the client was created with WithVersionCheck and
+// the API version was not found compatible yet. Once the outcome is known, i.e. the
+// version is compatible or not, it is remembered, while other errors lead to checking
+// again on the next request.
+func (c *Client) checkVersionOnce(ctx context.Context) error {
+	if c.versionCheck == nil {
+		return nil
+	}
+
+	c.versionCheck.mu.Lock()
+	defer c.versionCheck.mu.Unlock()
+
+	if c.versionCheck.done {
+		return c.versionCheck.err
+	}
+
+	err := c.CheckVersion(ctx)
+	if err == nil || errors.Is(err, ErrUnsupportedAPIVersion) {
+		c.versionCheck.done = true
+		c.versionCheck.err = err
+	}
+
+	return err
+}
+
+// majorVersion returns the major part of a version such as v1.2.0.
+func majorVersion(version string) string {
+	return strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+}
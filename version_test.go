@@ -0,0 +1,110 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		version     string
+		expectedErr error
+	}{
+		{
+			name:    "OK - same version",
+			version: "v1",
+		},
+		{
+			name:    "OK - same major version",
+			version: "v1.4.2",
+		},
+		{
+			name:        "Not OK - other major version",
+			version:     "v2.0.0",
+			expectedErr: ErrUnsupportedAPIVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/version", r.URL.Path)
+				_, _ = w.Write([]byte(`{"version":"` + tc.version + `"}`))
+			}))
+			defer ts.Close()
+
+			err := NewClient(ts.URL).CheckVersion(context.Background())
+
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithVersionCheck(t *testing.T) {
+	var versionRequests, fetchRequests int
+	version := "v1.0.0"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			versionRequests++
+			_, _ = w.Write([]byte(`{"version":"` + version + `"}`))
+			return
+		}
+		fetchRequests++
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	client := NewClient(ts.URL, WithVersionCheck())
+	for i := 0; i < 2; i++ {
+		_, err := client.Fetch(ctx, uuid.New())
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, versionRequests)
+	assert.Equal(t, 2, fetchRequests)
+
+	version = "v2.0.0"
+	client = NewClient(ts.URL, WithVersionCheck())
+	for i := 0; i < 2; i++ {
+		_, err := client.Fetch(ctx, uuid.New())
+		assert.True(t, errors.Is(err, ErrUnsupportedAPIVersion))
+	}
+	assert.Equal(t, 2, versionRequests)
+	assert.Equal(t, 2, fetchRequests)
+}
+
+func TestWithVersionCheckSendsClientHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "tenant-1", r.Header.Get(defaultTenantHeader))
+		assert.Equal(t, defaultAccept, r.Header.Get("Accept"))
+
+		if r.URL.Path == "/version" {
+			_, _ = w.Write([]byte(`{"version":"v1.0.0"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL, WithVersionCheck(), WithAPIKey("secret"))
+
+	_, err := client.Fetch(TenantContext(context.Background(), "tenant-1"), uuid.New())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package form3
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Watch polls the organisation account with the given ID, waiting interval between
+// fetches, and calls onChange whenever it differs from the previous fetch, as told by
+// OrganisationAccount.Equal. onChange is first called with the account as initially
+// fetched. Watch blocks until ctx is done, returning its error, or until a fetch
+// fails, returning that error, e.g. ErrNotFound once the account is deleted, or
+// ErrClientClosed once the client is closed.
+func (c *Client) Watch(ctx context.Context, id uuid.UUID, interval time.Duration, onChange func(OrganisationAccount)) error {
+	if interval <= 0 {
+		return errors.New("watch interval must be positive")
+	}
+
+	if !c.lifecycle.start() {
+		return ErrClientClosed
+	}
+	defer c.lifecycle.done()
+
+	parent := ctx
+	ctx, cancel := c.lifecycle.bind(ctx)
+	defer cancel()
+
+	var (
+		previous OrganisationAccount
+		fetched  bool
+	)
+	for {
+		current, err := c.Fetch(ctx, id)
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		if err != nil {
+			return err
+		}
+
+		if !fetched || !current.Equal(previous) {
+			onChange(current)
+		}
+		previous, fetched = current, true
+
+		err = sleepContext(ctx, interval)
+		if parent.Err() != nil {
+			return parent.Err()
+		}
+		if err != nil {
+			return ErrClientClosed
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package form3
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	id := uuid.MustParse("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc")
+
+	// versions of the account returned by successive fetches, the last one repeating
+	versions := []int{1, 1, 2, 2, 2, 3}
+	var fetches int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := versions[len(versions)-1]
+		if fetches < len(versions) {
+			version = versions[fetches]
+		}
+		fetches++
+
+		_ = json.NewEncoder(w).Encode(map[string]OrganisationAccount{
+			"data": {ID: id, Type: AccountType, Version: version},
+		})
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes []int
+	err := client.Watch(ctx, id, time.Millisecond, func(account OrganisationAccount) {
+		changes = append(changes, account.Version)
+		if account.Version == 3 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []int{1, 2, 3}, changes)
+	assert.Equal(t, len(versions), fetches)
+}
+
+func TestWatchFetchError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	client := NewClient(ts.URL)
+
+	var called bool
+	err := client.Watch(context.Background(), uuid.New(), time.Millisecond, func(OrganisationAccount) {
+		called = true
+	})
+
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, called)
+
+	err = client.Watch(context.Background(), uuid.New(), 0, func(OrganisationAccount) {})
+	assert.Error(t, err)
+}